@@ -2,6 +2,8 @@ package color
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 )
 
 // Attribute defines a single SGR Code.
@@ -9,61 +11,84 @@ type Attribute uint64
 
 // String returns color code as a string.
 func (a Attribute) String() string {
+	if a.isExtended() {
+		return a.extendedCode()
+	}
 	return attributeToSGRCode[a]
 }
 
 // Name returns a human readable name for an Attribute.
 func (a Attribute) Name() string {
-	m := map[Attribute]string{
-		Reset:        "Reset",
-		Bold:         "Bold",
-		Faint:        "Faint",
-		Italic:       "Italic",
-		Underline:    "Underline",
-		BlinkSlow:    "BlinkSlow",
-		BlinkRapid:   "BlinkRapid",
-		ReverseVideo: "ReverseVideo",
-		Concealed:    "Concealed",
-		CrossedOut:   "CrossedOut",
-		FgBlack:      "FgBlack",
-		FgRed:        "FgRed",
-		FgGreen:      "FgGreen",
-		FgYellow:     "FgYellow",
-		FgBlue:       "FgBlue",
-		FgMagenta:    "FgMagenta",
-		FgCyan:       "FgCyan",
-		FgWhite:      "FgWhite",
-		FgHiBlack:    "FgHiBlack",
-		FgHiRed:      "FgHiRed",
-		FgHiGreen:    "FgHiGreen",
-		FgHiYellow:   "FgHiYellow",
-		FgHiBlue:     "FgHiBlue",
-		FgHiMagenta:  "FgHiMagenta",
-		FgHiCyan:     "FgHiCyan",
-		FgHiWhite:    "FgHiWhite",
-		BgBlack:      "BgBlack",
-		BgRed:        "BgRed",
-		BgGreen:      "BgGreen",
-		BgYellow:     "BgYellow",
-		BgBlue:       "BgBlue",
-		BgMagenta:    "BgMagenta",
-		BgCyan:       "BgCyan",
-		BgWhite:      "BgWhite",
-		BgHiBlack:    "BgHiBlack",
-		BgHiRed:      "BgHiRed",
-		BgHiGreen:    "BgHiGreen",
-		BgHiYellow:   "BgHiYellow",
-		BgHiBlue:     "BgHiBlue",
-		BgHiMagenta:  "BgHiMagenta",
-		BgHiCyan:     "BgHiCyan",
-		BgHiWhite:    "BgHiWhite",
+	if a.isExtended() {
+		return a.extendedName()
 	}
-	if s, ok := m[a]; ok {
+	if s, ok := attributeNames[a]; ok {
 		return s
 	}
 	return fmt.Sprintf("unknown color %d", a)
 }
 
+// attributeNames holds the names of the attributes without a parameter.
+var attributeNames = map[Attribute]string{
+	Reset:        "Reset",
+	Bold:         "Bold",
+	Faint:        "Faint",
+	Italic:       "Italic",
+	Underline:    "Underline",
+	BlinkSlow:    "BlinkSlow",
+	BlinkRapid:   "BlinkRapid",
+	ReverseVideo: "ReverseVideo",
+	Concealed:    "Concealed",
+	CrossedOut:   "CrossedOut",
+	FgBlack:      "FgBlack",
+	FgRed:        "FgRed",
+	FgGreen:      "FgGreen",
+	FgYellow:     "FgYellow",
+	FgBlue:       "FgBlue",
+	FgMagenta:    "FgMagenta",
+	FgCyan:       "FgCyan",
+	FgWhite:      "FgWhite",
+	FgHiBlack:    "FgHiBlack",
+	FgHiRed:      "FgHiRed",
+	FgHiGreen:    "FgHiGreen",
+	FgHiYellow:   "FgHiYellow",
+	FgHiBlue:     "FgHiBlue",
+	FgHiMagenta:  "FgHiMagenta",
+	FgHiCyan:     "FgHiCyan",
+	FgHiWhite:    "FgHiWhite",
+	BgBlack:      "BgBlack",
+	BgRed:        "BgRed",
+	BgGreen:      "BgGreen",
+	BgYellow:     "BgYellow",
+	BgBlue:       "BgBlue",
+	BgMagenta:    "BgMagenta",
+	BgCyan:       "BgCyan",
+	BgWhite:      "BgWhite",
+	BgHiBlack:    "BgHiBlack",
+	BgHiRed:      "BgHiRed",
+	BgHiGreen:    "BgHiGreen",
+	BgHiYellow:   "BgHiYellow",
+	BgHiBlue:     "BgHiBlue",
+	BgHiMagenta:  "BgHiMagenta",
+	BgHiCyan:     "BgHiCyan",
+	BgHiWhite:    "BgHiWhite",
+
+	DoubleUnderline: "DoubleUnderline",
+	CurlyUnderline:  "CurlyUnderline",
+	DottedUnderline: "DottedUnderline",
+	Overline:        "Overline",
+	NormalIntensity: "NormalIntensity",
+	NotItalic:       "NotItalic",
+	NoUnderline:     "NoUnderline",
+	NotBlinking:     "NotBlinking",
+	NotReversed:     "NotReversed",
+	Revealed:        "Revealed",
+	NotCrossedOut:   "NotCrossedOut",
+	NotOverlined:    "NotOverlined",
+	DefaultFg:       "DefaultFg",
+	DefaultBg:       "DefaultBg",
+}
+
 const (
 	Reset Attribute = 1 << iota
 	Bold
@@ -109,6 +134,30 @@ const (
 	BgHiWhite
 )
 
+// Attributes added after the fixed set above are extended attributes without a parameter, so that the fixed
+// bits stay free.
+const (
+	DoubleUnderline = extended | kindDoubleUnderline<<kindShift
+	CurlyUnderline  = extended | kindCurlyUnderline<<kindShift
+	DottedUnderline = extended | kindDottedUnderline<<kindShift
+	Overline        = extended | kindOverline<<kindShift
+
+	// The following attributes turn off a previous attribute, they are used to end a style without resetting
+	// all of them.
+
+	NormalIntensity = extended | kindNormalIntensity<<kindShift // turns off Bold and Faint
+	NotItalic       = extended | kindNotItalic<<kindShift
+	NoUnderline     = extended | kindNoUnderline<<kindShift // turns off all underline styles
+	NotBlinking     = extended | kindNotBlinking<<kindShift
+	NotReversed     = extended | kindNotReversed<<kindShift
+	Revealed        = extended | kindRevealed<<kindShift // turns off Concealed
+	NotCrossedOut   = extended | kindNotCrossedOut<<kindShift
+	NotOverlined    = extended | kindNotOverlined<<kindShift
+	DefaultFg       = extended | kindDefaultFg<<kindShift
+	DefaultBg       = extended | kindDefaultBg<<kindShift
+)
+
+// attributeToSGRCode holds the codes of the attributes without a parameter.
 var attributeToSGRCode = map[Attribute]string{
 	Reset:        "0",
 	Bold:         "1",
@@ -152,13 +201,195 @@ var attributeToSGRCode = map[Attribute]string{
 	BgHiMagenta:  "105",
 	BgHiCyan:     "106",
 	BgHiWhite:    "107",
+
+	DoubleUnderline: "21",
+	CurlyUnderline:  "4:3",
+	DottedUnderline: "4:4",
+	Overline:        "53",
+	NormalIntensity: "22",
+	NotItalic:       "23",
+	NoUnderline:     "24",
+	NotBlinking:     "25",
+	NotReversed:     "27",
+	Revealed:        "28",
+	NotCrossedOut:   "29",
+	NotOverlined:    "55",
+	DefaultFg:       "39",
+	DefaultBg:       "49",
+}
+
+// Attributes that carry a parameter, such as an entry of the 256 color palette or a 24 bit color, don't fit
+// the single bit scheme used by the fixed attributes. They are tagged with the top bit, store their kind in
+// the remaining bits of the high byte and their parameter in the low bits.
+const (
+	extended  Attribute = 1 << 63
+	kindShift           = 56
+	kindMask  Attribute = 0x7f << kindShift
+	valueMask Attribute = 0xffffff
+)
+
+const (
+	kindFg256 Attribute = iota + 1
+	kindBg256
+	kindFgRGB
+	kindBgRGB
+	kindUl256
+	kindUlRGB
+	kindDoubleUnderline
+	kindCurlyUnderline
+	kindDottedUnderline
+	kindOverline
+	kindNormalIntensity
+	kindNotItalic
+	kindNoUnderline
+	kindNotBlinking
+	kindNotReversed
+	kindRevealed
+	kindNotCrossedOut
+	kindNotOverlined
+	kindDefaultFg
+	kindDefaultBg
+)
+
+// Fg256 returns an Attribute which sets the foreground to color n of the 256 color palette.
+func Fg256(n uint8) Attribute {
+	return extended | kindFg256<<kindShift | Attribute(n)
+}
+
+// Bg256 returns an Attribute which sets the background to color n of the 256 color palette.
+func Bg256(n uint8) Attribute {
+	return extended | kindBg256<<kindShift | Attribute(n)
+}
+
+// FgRGB returns an Attribute which sets the foreground to a 24 bit color. Not all terminals support 24 bit
+// color.
+func FgRGB(r, g, b uint8) Attribute {
+	return extended | kindFgRGB<<kindShift | packRGB(r, g, b)
+}
+
+// BgRGB returns an Attribute which sets the background to a 24 bit color.
+func BgRGB(r, g, b uint8) Attribute {
+	return extended | kindBgRGB<<kindShift | packRGB(r, g, b)
+}
+
+// Hex returns an Attribute which sets the foreground to the 24 bit color described by s. The color may be
+// given as #rrggbb or #rgb, the leading # is optional. An Attribute without any SGR code is returned when s
+// can't be parsed.
+func Hex(s string) Attribute {
+	r, g, b, ok := parseHex(s)
+	if !ok {
+		return 0
+	}
+	return FgRGB(r, g, b)
+}
+
+// BgHex returns an Attribute which sets the background to the 24 bit color described by s. See Hex for the
+// accepted formats.
+func BgHex(s string) Attribute {
+	r, g, b, ok := parseHex(s)
+	if !ok {
+		return 0
+	}
+	return BgRGB(r, g, b)
+}
+
+// Ul256 returns an Attribute which sets the color of underlines to color n of the 256 color palette. Few
+// terminals support colored underlines, others ignore it.
+func Ul256(n uint8) Attribute {
+	return extended | kindUl256<<kindShift | Attribute(n)
+}
+
+// UlRGB returns an Attribute which sets the color of underlines to a 24 bit color.
+func UlRGB(r, g, b uint8) Attribute {
+	return extended | kindUlRGB<<kindShift | packRGB(r, g, b)
+}
+
+func packRGB(r, g, b uint8) Attribute {
+	return Attribute(r)<<16 | Attribute(g)<<8 | Attribute(b)
+}
+
+func parseHex(s string) (r, g, b uint8, ok bool) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) != 6 {
+		return 0, 0, 0, false
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return uint8(v >> 16), uint8(v >> 8), uint8(v), true
+}
+
+func (a Attribute) isExtended() bool {
+	return a&extended != 0
+}
+
+func (a Attribute) kind() Attribute {
+	return (a & kindMask) >> kindShift
+}
+
+func (a Attribute) value() uint32 {
+	return uint32(a & valueMask)
+}
+
+func (a Attribute) rgb() (r, g, b uint8) {
+	v := a.value()
+	return uint8(v >> 16), uint8(v >> 8), uint8(v)
+}
+
+func (a Attribute) extendedCode() string {
+	switch a.kind() {
+	case kindFgRGB:
+		r, g, b := a.rgb()
+		return fmt.Sprintf("38;2;%d;%d;%d", r, g, b)
+	case kindBgRGB:
+		r, g, b := a.rgb()
+		return fmt.Sprintf("48;2;%d;%d;%d", r, g, b)
+	case kindFg256:
+		return "38;5;" + strconv.Itoa(int(a.value()))
+	case kindBg256:
+		return "48;5;" + strconv.Itoa(int(a.value()))
+	case kindUl256:
+		return "58;5;" + strconv.Itoa(int(a.value()))
+	case kindUlRGB:
+		r, g, b := a.rgb()
+		return fmt.Sprintf("58;2;%d;%d;%d", r, g, b)
+	}
+	return attributeToSGRCode[a]
+}
+
+func (a Attribute) extendedName() string {
+	switch a.kind() {
+	case kindFgRGB:
+		r, g, b := a.rgb()
+		return fmt.Sprintf("FgRGB(%d,%d,%d)", r, g, b)
+	case kindBgRGB:
+		r, g, b := a.rgb()
+		return fmt.Sprintf("BgRGB(%d,%d,%d)", r, g, b)
+	case kindFg256:
+		return fmt.Sprintf("Fg256(%d)", a.value())
+	case kindBg256:
+		return fmt.Sprintf("Bg256(%d)", a.value())
+	case kindUl256:
+		return fmt.Sprintf("Ul256(%d)", a.value())
+	case kindUlRGB:
+		r, g, b := a.rgb()
+		return fmt.Sprintf("UlRGB(%d,%d,%d)", r, g, b)
+	}
+	if s, ok := attributeNames[a]; ok {
+		return s
+	}
+	return fmt.Sprintf("unknown color %d", a)
 }
 
 func to_codes(attrs []Attribute) []string {
 	codes := make([]string, len(attrs))
 	for i := 0; i < len(attrs); i++ {
-		code, ok := attributeToSGRCode[attrs[i]]
-		if !ok {
+		code := attrs[i].String()
+		if code == "" {
 			return nil
 		}
 		codes[i] = code
@@ -166,10 +397,9 @@ func to_codes(attrs []Attribute) []string {
 	return codes
 }
 
-func to_key(attr []Attribute) Attribute {
-	var key Attribute
-	for i := 0; i < len(attr); i++ {
-		key |= attr[i]
-	}
-	return key
+// to_key normalizes attrs so that sets of attributes which render the same share a key regardless of the
+// order they're given in. Duplicates are dropped, the last foreground and background color win and Reset
+// clears the attributes before it. Attributes without an SGR code are ignored.
+func to_key(attr []Attribute) Style {
+	return NewStyle().With(attr...)
 }
@@ -14,7 +14,7 @@ func cache() *colorCache {
 	return cacheSingleton
 }
 
-type colorMap map[Attribute]*Color
+type colorMap map[Style]*Color
 
 type colorCache struct {
 	sync.RWMutex
@@ -27,15 +27,17 @@ func (cc *colorCache) value(attrs ...Attribute) *Color {
 		return v
 	}
 	cc.Lock()
-	v := &Color{
-		colorStart: chainSGRCodes(attrs),
+	defer cc.Unlock()
+	// another goroutine may have created the color while the lock was released
+	if v, ok := cc.cache[key]; ok {
+		return v
 	}
+	v := newColor(key.Attributes())
 	cc.cache[key] = v
-	cc.Unlock()
 	return v
 }
 
-func (vc *colorCache) getIfExists(key Attribute) *Color {
+func (vc *colorCache) getIfExists(key Style) *Color {
 	vc.RLock()
 	if v, ok := vc.cache[key]; ok {
 		vc.RUnlock()
@@ -1,6 +1,9 @@
 package color
 
-import "testing"
+import (
+	"sync"
+	"testing"
+)
 
 func TestColorCache(t *testing.T) {
 	v := colorCache{
@@ -23,4 +26,76 @@ func TestColorCache(t *testing.T) {
 	if c1 != c2 {
 		t.Fatal("expect c2 to be same as c1")
 	}
+
+	// 256 colors must not be folded together, Fg256(1) | Fg256(2) has the same bits as Fg256(3)
+	c3 := New(Fg256(1), Fg256(2))
+	c4 := New(Fg256(3))
+	if c3 == c4 {
+		t.Fatal("expect c3 and c4 to be different colors")
+	}
+}
+
+func TestColorCacheNormalization(t *testing.T) {
+	t.Parallel()
+	v := colorCache{
+		cache: make(colorMap),
+	}
+	tt := []struct {
+		name  string
+		attrs []Attribute
+		want  string
+	}{
+		{"last foreground wins", []Attribute{FgBlue, FgRed}, "\x1b[31m"},
+		{"last foreground wins reversed", []Attribute{FgRed, FgBlue}, "\x1b[34m"},
+		{"last background wins", []Attribute{BgRed, Bold, Bg256(7)}, "\x1b[1;48;5;7m"},
+		{"duplicates", []Attribute{Bold, Bold}, "\x1b[1m"},
+		{"single", []Attribute{Bold}, "\x1b[1m"},
+		{"stable order", []Attribute{BgBlack, FgWhite, Underline, Bold}, "\x1b[1;4;37;40m"},
+		{"reset clears", []Attribute{Bold, Reset, FgRed}, "\x1b[31m"},
+		{"reset only", []Attribute{Reset}, colorReset},
+		{"unknown ignored", []Attribute{1 << 50, Italic}, "\x1b[3m"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assertEqualS(t, tc.want, v.value(tc.attrs...).colorStart)
+		})
+	}
+	if v.value(FgBlue, FgRed) == v.value(FgRed, FgBlue) {
+		t.Fatal("expect colors with a different last foreground to differ")
+	}
+	if v.value(Bold, Bold) != v.value(Bold) {
+		t.Fatal("expect duplicates to share the color")
+	}
+}
+
+func TestColorCacheConcurrentFirstUse(t *testing.T) {
+	t.Parallel()
+	v := colorCache{
+		cache: make(colorMap),
+	}
+	orders := [][]Attribute{
+		{FgRed, BgWhite, Underline},
+		{Underline, FgRed, BgWhite},
+		{BgWhite, Underline, FgRed, FgRed},
+	}
+	const n = 64
+	results := make([]*Color, n)
+	var start, done sync.WaitGroup
+	start.Add(1)
+	for i := 0; i < n; i++ {
+		done.Add(1)
+		go func(i int) {
+			defer done.Done()
+			start.Wait()
+			results[i] = v.value(orders[i%len(orders)]...)
+		}(i)
+	}
+	start.Done()
+	done.Wait()
+	for i := 1; i < n; i++ {
+		if results[i] != results[0] {
+			t.Fatalf("expect every goroutine to get the same color, %d differs", i)
+		}
+	}
+	assertEqualS(t, "\x1b[4;31;47m", results[0].colorStart)
 }
@@ -11,6 +11,8 @@ const (
 	lineFeed   = "\n"
 	delimiter  = ";"
 	colorReset = "\x1b[0m"
+	shortReset = "\x1b[m"
+	clearLine  = "\x1b[2K"
 )
 
 func chainSGRCodes(a []Attribute) string {
@@ -39,6 +41,26 @@ func chainSGRCodes(a []Attribute) string {
 // Color contains methods to create colored strings of text.
 type Color struct {
 	colorStart string
+	// starts holds colorStart converted for each Profile.
+	starts [numProfiles]string
+}
+
+func newColor(attrs []Attribute) *Color {
+	v := &Color{
+		colorStart: chainSGRCodes(attrs),
+	}
+	for p := TrueColor; p < numProfiles; p++ {
+		v.starts[p] = chainSGRCodes(downsample(attrs, p))
+	}
+	return v
+}
+
+// start returns the escape sequence which begins colored text on a terminal with profile p.
+func (v Color) start(p Profile) string {
+	if p < TrueColor || p >= numProfiles {
+		return v.colorStart
+	}
+	return v.starts[p]
 }
 
 // New creates a Color. It takes a list of Attributes to define
@@ -49,29 +71,29 @@ func New(attrs ...Attribute) *Color {
 
 // Sprint returns text decorated with the display Attributes passed to Color constructor function.
 func (v Color) Sprint(a ...interface{}) string {
-	if Enabled() {
-		return v.wrap(fmt.Sprint(a...))
-	}
-	return fmt.Sprint(a...)
+	return v.render(Enabled(), ActiveProfile(), fmt.Sprint(a...))
 }
 
 // Sprint formats according to the format specifier and returns text decorated with the display Attributes
 // passed to Color constructor function.
 func (v Color) Sprintf(format string, a ...interface{}) string {
-	if Enabled() {
-		return v.wrap(fmt.Sprintf(format, a...))
-	}
-	return fmt.Sprintf(format, a...)
+	return v.render(Enabled(), ActiveProfile(), fmt.Sprintf(format, a...))
 }
 
 // Sprint returns text decorated with the display Attributes and terminated by a line feed.
 func (v Color) Sprintln(a ...interface{}) string {
-	var s string
-	if Enabled() {
-		s = v.wrap(fmt.Sprint(a...))
-	} else {
-		s = fmt.Sprint(a...)
+	return appendLineFeed(v.render(Enabled(), ActiveProfile(), fmt.Sprint(a...)))
+}
+
+// render decorates s for output with profile p, or returns it unchanged when colors are off.
+func (v Color) render(on bool, p Profile, s string) string {
+	if on {
+		return v.wrap(p, s)
 	}
+	return s
+}
+
+func appendLineFeed(s string) string {
 	if !strings.HasSuffix(s, lineFeed) {
 		s += lineFeed
 	}
@@ -99,17 +121,58 @@ func (v Color) SprintlnFunc() func(a ...interface{}) string {
 	}
 }
 
-func (v Color) wrap(s ...string) string {
+// wrap surrounds s with the escape sequences for profile p. Text colored by another Color may be nested in s,
+// its reset is followed by the start sequence again so the rest of s keeps this color.
+func (v Color) wrap(p Profile, s ...string) string {
+	start := v.start(p)
 	var b strings.Builder
-	b.Grow(len(v.colorStart) + len(s) + len(colorReset))
-	b.WriteString(v.colorStart)
+	b.Grow(len(start) + len(s) + len(colorReset))
+	b.WriteString(start)
 	for i := 0; i < len(s); i++ {
-		b.WriteString(s[i])
+		writeNested(&b, s[i], start, i == len(s)-1)
 	}
 	b.WriteString(colorReset)
 	return b.String()
 }
 
+// writeNested writes s to b and restores the outer color start after every reset found in s. A reset at the
+// very end of the last string doesn't need restoring since the outer color ends there as well.
+func writeNested(b *strings.Builder, s, start string, last bool) {
+	if start == colorReset {
+		b.WriteString(s)
+		return
+	}
+	for {
+		i, n := indexReset(s)
+		if i < 0 {
+			b.WriteString(s)
+			return
+		}
+		b.WriteString(s[:i+n])
+		s = s[i+n:]
+		if last && s == "" {
+			return
+		}
+		b.WriteString(start)
+	}
+}
+
+// indexReset returns the index and length of the first reset sequence in s, or -1 if there is none.
+func indexReset(s string) (int, int) {
+	for i := 0; i < len(s); i++ {
+		if s[i] != esc {
+			continue
+		}
+		if strings.HasPrefix(s[i:], colorReset) {
+			return i, len(colorReset)
+		}
+		if strings.HasPrefix(s[i:], shortReset) {
+			return i, len(shortReset)
+		}
+	}
+	return -1, 0
+}
+
 func colorString(format string, attr Attribute, a ...interface{}) string {
 	return cache().value(attr).Sprintf(format, a...)
 }
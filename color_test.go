@@ -13,7 +13,8 @@ type helperFuncString func(fmt string, a ...interface{}) string
 
 func newMockConsole(out io.Writer) *Console {
 	return &Console{
-		current: out,
+		colored:    out,
+		noncolored: out,
 	}
 }
 
@@ -143,7 +144,7 @@ func TestMultiAttribute(t *testing.T) {
 	cons := newMockConsole(&buff)
 	col := New(FgWhite, Bold, Underline)
 	_, _ = cons.Print(col, "bold white")
-	want := "\x1b[37;1;4mbold white\x1b[0m"
+	want := "\x1b[1;4;37mbold white\x1b[0m"
 	got := buff.String()
 	t.Log(got)
 	if got != want {
@@ -151,6 +152,117 @@ func TestMultiAttribute(t *testing.T) {
 	}
 }
 
+func TestColor256(t *testing.T) {
+	// can't be parallel since Sprint uses the global profile
+	defer ReloadEnv()
+	SetMode(Auto)
+	SetProfile(TrueColor)
+	tt := []struct {
+		name  string
+		attrs []Attribute
+		want  string
+	}{
+		{"fg", []Attribute{Fg256(208)}, "\x1b[38;5;208mtext\x1b[0m"},
+		{"bg", []Attribute{Bg256(0)}, "\x1b[48;5;0mtext\x1b[0m"},
+		{"fg and bg", []Attribute{Fg256(15), Bg256(196)}, "\x1b[38;5;15;48;5;196mtext\x1b[0m"},
+		{"with fixed attributes", []Attribute{Bold, Fg256(33), Underline}, "\x1b[1;4;38;5;33mtext\x1b[0m"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var buff bytes.Buffer
+			cons := newMockConsole(&buff)
+			_, _ = cons.Print(New(tc.attrs...), "text")
+			assertEqualS(t, tc.want, buff.String())
+			assertEqualS(t, tc.want, New(tc.attrs...).Sprint("text"))
+		})
+	}
+}
+
+func TestColor256Name(t *testing.T) {
+	t.Parallel()
+	assertEqualS(t, "Fg256(42)", Fg256(42).Name())
+	assertEqualS(t, "Bg256(255)", Bg256(255).Name())
+	assertEqualS(t, "38;5;42", Fg256(42).String())
+}
+
+func TestUnderlineAttributes(t *testing.T) {
+	t.Parallel()
+	assertEqualS(t, "Ul256(208)", Ul256(208).Name())
+	assertEqualS(t, "58;5;208", Ul256(208).String())
+	assertEqualS(t, "UlRGB(1,2,3)", UlRGB(1, 2, 3).Name())
+	assertEqualS(t, "58;2;1;2;3", UlRGB(1, 2, 3).String())
+	assertEqualS(t, "CurlyUnderline", CurlyUnderline.Name())
+	assertEqualS(t, "4:3", CurlyUnderline.String())
+	assertEqualS(t, "NotOverlined", NotOverlined.Name())
+	assertEqualS(t, "55", NotOverlined.String())
+	for _, a := range []Attribute{DoubleUnderline, Overline, NormalIntensity, DefaultFg, DefaultBg} {
+		if !a.isExtended() || a.value() != 0 {
+			t.Fatalf("expected %s to be an extended attribute without a parameter", a.Name())
+		}
+	}
+}
+
+func TestColorRGB(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		name  string
+		attrs []Attribute
+		want  string
+	}{
+		{"fg", []Attribute{FgRGB(255, 136, 0)}, "\x1b[38;2;255;136;0mtext\x1b[0m"},
+		{"bg", []Attribute{BgRGB(0, 0, 0)}, "\x1b[48;2;0;0;0mtext\x1b[0m"},
+		{"hex", []Attribute{Hex("#ff8800")}, "\x1b[38;2;255;136;0mtext\x1b[0m"},
+		{"short hex", []Attribute{Hex("f80")}, "\x1b[38;2;255;136;0mtext\x1b[0m"},
+		{"bg hex", []Attribute{BgHex("#0A0b0C")}, "\x1b[48;2;10;11;12mtext\x1b[0m"},
+		{"with fixed attributes", []Attribute{Bold, Hex("#102030"), Underline},
+			"\x1b[1;4;38;2;16;32;48mtext\x1b[0m"},
+		{"invalid hex", []Attribute{Hex("#ff88zz")}, "\x1b[0mtext\x1b[0m"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var buff bytes.Buffer
+			cons := newMockConsole(&buff)
+			_, _ = cons.Print(New(tc.attrs...), "text")
+			assertEqualS(t, tc.want, buff.String())
+		})
+	}
+	assertEqualS(t, "FgRGB(1,2,3)", FgRGB(1, 2, 3).Name())
+	assertEqualS(t, "BgRGB(255,255,255)", BgHex("#fff").Name())
+}
+
+func TestNestedColors(t *testing.T) {
+	t.Parallel()
+	red := New(FgRed)
+	bold := New(Bold)
+	tt := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"inner in the middle", red.wrap(TrueColor, "error in "+bold.wrap(TrueColor, "file")+" at line 3"),
+			"\x1b[31merror in \x1b[1mfile\x1b[0m\x1b[31m at line 3\x1b[0m"},
+		{"inner at the end", red.wrap(TrueColor, "error in "+bold.wrap(TrueColor, "file")),
+			"\x1b[31merror in \x1b[1mfile\x1b[0m\x1b[0m"},
+		{"inner at the end of an earlier string", red.wrap(TrueColor, bold.wrap(TrueColor, "a"), "b"),
+			"\x1b[31m\x1b[1ma\x1b[0m\x1b[31mb\x1b[0m"},
+		{"short reset", red.wrap(TrueColor, "a\x1b[mb"), "\x1b[31ma\x1b[m\x1b[31mb\x1b[0m"},
+		{"three levels",
+			New(BgBlue).wrap(TrueColor, "<"+red.wrap(TrueColor, "("+bold.wrap(TrueColor, "x")+")")+">"),
+			"\x1b[44m<\x1b[31m(\x1b[1mx\x1b[0m\x1b[44m\x1b[31m)\x1b[0m\x1b[44m>\x1b[0m"},
+		{"no color", New().wrap(TrueColor, "a"+bold.wrap(TrueColor, "b")+"c"), "\x1b[0ma\x1b[1mb\x1b[0mc\x1b[0m"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assertEqualS(t, tc.want, tc.got)
+		})
+	}
+
+	spans := Parse(red.wrap(TrueColor, "error in "+bold.wrap(TrueColor, "file")+" here"))
+	if len(spans) != 3 || spans[2].Style != NewStyle().Fg(FgRed) {
+		t.Fatalf("expected the outer color after the nested span got %v", spans)
+	}
+}
+
 func TestMissingAttribute(t *testing.T) {
 	t.Parallel()
 	cache().clear()
@@ -230,10 +342,10 @@ func TestHelperStdoutFuncs(t *testing.T) {
 		t.Run(tc.code.String()+"_stdout", func(t *testing.T) {
 			var buff bytes.Buffer
 			cons := Stdout()
-			oldWriter := cons.current
-			cons.current = &buff
+			colored, noncolored := cons.colored, cons.noncolored
+			cons.colored, cons.noncolored = &buff, &buff
 			defer func() {
-				cons.current = oldWriter
+				cons.colored, cons.noncolored = colored, noncolored
 			}()
 
 			tc.testStdout("color - %q", tc.code.Name())
@@ -244,10 +356,10 @@ func TestHelperStdoutFuncs(t *testing.T) {
 		t.Run(tc.code.String()+"_stderr", func(t *testing.T) {
 			var buff bytes.Buffer
 			cons := Stderr()
-			oldWriter := cons.current
-			cons.current = &buff
+			colored, noncolored := cons.colored, cons.noncolored
+			cons.colored, cons.noncolored = &buff, &buff
 			defer func() {
-				cons.current = oldWriter
+				cons.colored, cons.noncolored = colored, noncolored
 			}()
 
 			tc.testStdErr("color - %q", tc.code.Name())
@@ -258,10 +370,10 @@ func TestHelperStdoutFuncs(t *testing.T) {
 
 func BenchmarkColorFuncs(b *testing.B) {
 	cons := Stdout()
-	oldWriter := cons.current
-	cons.current = ioutil.Discard
+	colored, noncolored := cons.colored, cons.noncolored
+	cons.colored, cons.noncolored = ioutil.Discard, ioutil.Discard
 	defer func() {
-		cons.current = oldWriter
+		cons.colored, cons.noncolored = colored, noncolored
 	}()
 
 	for i := 0; i < b.N; i++ {
@@ -273,10 +385,10 @@ func BenchmarkColorFuncs(b *testing.B) {
 
 func BenchmarkColorFuncsParallel(b *testing.B) {
 	cons := Stdout()
-	oldWriter := cons.current
-	cons.current = ioutil.Discard
+	colored, noncolored := cons.colored, cons.noncolored
+	cons.colored, cons.noncolored = ioutil.Discard, ioutil.Discard
 	defer func() {
-		cons.current = oldWriter
+		cons.colored, cons.noncolored = colored, noncolored
 	}()
 
 	b.RunParallel(func(pb *testing.PB) {
@@ -2,6 +2,7 @@
 package color
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
@@ -9,23 +10,24 @@ import (
 	"sync"
 
 	"github.com/mattn/go-colorable"
+	"github.com/mattn/go-isatty"
 )
 
-var noColor bool
-var noColorLock sync.RWMutex
-
-// Disable is used to turn color output on and off globally.
+// Disable is used to turn color output on and off globally. Passing false restores the default Auto mode.
+//
+// Deprecated: use SetMode with Never or Auto.
 func Disable(flag bool) {
-	noColorLock.Lock()
-	defer noColorLock.Unlock()
-	noColor = flag
+	if flag {
+		SetMode(Never)
+		return
+	}
+	SetMode(Auto)
 }
 
-// Enabled returns flag indicating whether colors are enabled or not.
+// Enabled returns flag indicating whether colors are enabled or not, which is the case unless the global mode
+// is Never.
 func Enabled() bool {
-	noColorLock.RLock()
-	defer noColorLock.RUnlock()
-	return !noColor
+	return Mode() != Never
 }
 
 var stdout *Console // Don't use directly use Stdout() instead.
@@ -55,85 +57,323 @@ type Console struct {
 	sync.Mutex
 	colored        io.Writer
 	noncolored     io.Writer
-	current        io.Writer
 	fileDescriptor uintptr
+	profile        Profile
+	mode           ColorMode
+	terminal       bool
+	hyperlinks     bool
+	// set holds the colors passed to Set which haven't been unset, the last one is active.
+	set []*Color
+	// status is the line drawn by an active progress bar or spinner. Other output clears it first and draws it
+	// again below.
+	status string
+	// pending holds output which doesn't end a line yet while the status line is shown.
+	pending []byte
+}
+
+// ConsoleOption configures a Console when it's created.
+type ConsoleOption func(*Console)
+
+// WithMode sets the color mode of a new Console.
+func WithMode(m ColorMode) ConsoleOption {
+	return func(c *Console) {
+		c.mode = m
+	}
+}
+
+// WithProfile sets the profile of a new Console instead of using the active profile.
+func WithProfile(p Profile) ConsoleOption {
+	return func(c *Console) {
+		c.profile = p
+	}
 }
 
-// NewConsole creates a wrapper around out which will output platform independent colored text.
-func NewConsole(out *os.File) *Console {
+// NewConsole creates a wrapper around out which will output platform independent colored text. The console
+// is created in Auto mode, so it follows the global mode and strips colors when out isn't a terminal.
+func NewConsole(out *os.File, opts ...ConsoleOption) *Console {
+	fd := out.Fd()
 	c := &Console{
 		colored:        colorable.NewColorable(out),
-		noncolored:     colorable.NewNonColorable(out),
-		fileDescriptor: out.Fd(),
+		noncolored:     NewStripWriter(out),
+		fileDescriptor: fd,
+		profile:        ActiveProfile(),
+		terminal:       isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd),
 	}
-	if Enabled() {
-		c.current = c.colored
-		return c
+	c.hyperlinks = detectHyperlinks(os.Getenv, c.terminal)
+	return c.apply(opts)
+}
+
+// NewConsoleWriter creates a Console writing to out, for example a bytes.Buffer or a network connection.
+// Such writers aren't terminals, so colors are stripped unless the console or global mode is Always. When out
+// is an *os.File NewConsoleWriter is the same as NewConsole.
+func NewConsoleWriter(out io.Writer, opts ...ConsoleOption) *Console {
+	if f, ok := out.(*os.File); ok {
+		return NewConsole(f, opts...)
+	}
+	c := &Console{
+		colored:        out,
+		noncolored:     NewStripWriter(out),
+		fileDescriptor: invalidFd,
+		profile:        ActiveProfile(),
+		hyperlinks:     detectHyperlinks(os.Getenv, false),
+	}
+	return c.apply(opts)
+}
+
+// invalidFd is returned by Fd for consoles which don't write to a file.
+const invalidFd = ^uintptr(0)
+
+func (c *Console) apply(opts []ConsoleOption) *Console {
+	for _, opt := range opts {
+		opt(c)
 	}
-	c.current = c.noncolored
 	return c
 }
 
+// writer returns the writer for the mode of the console, see output.
+func (c *Console) writer() io.Writer {
+	w, _ := c.output()
+	return w
+}
+
+// output returns the writer for the mode of the console and whether it writes colors. Both are decided on
+// every call so that consoles in Auto mode follow changes of the global mode, and text built for the console
+// always matches the writer it goes to. The caller holds the lock.
+func (c *Console) output() (io.Writer, bool) {
+	if c.colorsOn() {
+		return c.colored, true
+	}
+	return c.noncolored, false
+}
+
+// resolvedMode returns the mode of the console with Auto replaced by the global mode, the caller holds the
+// lock.
+func (c *Console) resolvedMode() ColorMode {
+	if c.mode == Auto {
+		return Mode()
+	}
+	return c.mode
+}
+
+// colorsOn reports whether the console writes colors in its mode, the caller holds the lock.
+func (c *Console) colorsOn() bool {
+	switch c.resolvedMode() {
+	case Always:
+		return true
+	case Never:
+		return false
+	}
+	return c.terminal
+}
+
+// Fd returns the file descriptor the console writes to. Consoles created by NewConsoleWriter for writers
+// which aren't files return ^uintptr(0).
 func (c *Console) Fd() uintptr {
 	c.Lock()
 	defer c.Unlock()
 	return c.fileDescriptor
 }
 
-// DisableColors if true ANSI color information will be removed for this console object. Passing true will enable
-// colors for this Console, even if colors are disabled globally.
-func (c *Console) DisableColors(strip bool) {
+// IsTerminal reports whether the console writes to a terminal.
+func (c *Console) IsTerminal() bool {
+	c.Lock()
+	defer c.Unlock()
+	return c.terminal
+}
+
+// Mode returns the color mode of the console.
+func (c *Console) Mode() ColorMode {
 	c.Lock()
 	defer c.Unlock()
+	return c.mode
+}
+
+// SetMode sets when the console writes colors. Always and Never take precedence over the global mode, Auto
+// follows it.
+func (c *Console) SetMode(m ColorMode) {
+	c.Lock()
+	defer c.Unlock()
+	c.mode = m
+}
+
+// Profile returns the range of colors written by the console.
+func (c *Console) Profile() Profile {
+	c.Lock()
+	defer c.Unlock()
+	return c.profile
+}
+
+// SetProfile overrides the profile detected for the console. Colors the profile can't display are converted
+// to the nearest color it can.
+func (c *Console) SetProfile(p Profile) {
+	c.Lock()
+	defer c.Unlock()
+	c.profile = p
+}
+
+// DisableColors if true ANSI color information will be removed for this console object. Passing false will
+// enable colors for this Console, even if colors are disabled globally or the console isn't a terminal.
+//
+// Deprecated: use SetMode with Never or Always.
+func (c *Console) DisableColors(strip bool) {
 	if strip {
-		c.current = c.noncolored
+		c.SetMode(Never)
 		return
 	}
-	c.current = c.colored
+	c.SetMode(Always)
 }
 
-// Set will cause the color passed in as an argument to be written until Unset is called.
+// Set will cause the color passed in as an argument to be written until Unset is called. Calls to Set nest,
+// the color set before is restored by Unset and after colored text is printed.
 func (c *Console) Set(color *Color) {
 	c.Lock()
 	defer c.Unlock()
-	_, _ = c.current.Write([]byte(color.colorStart))
+	c.set = append(c.set, color)
+	_, _ = c.writer().Write([]byte(color.start(c.profile)))
 }
 
 // Unset will restore console output to default. It will undo colored console output defined from a call to Set.
+// If Set was called more than once, the color of the previous call is restored.
 func (c *Console) Unset() {
 	c.Lock()
 	defer c.Unlock()
-	_, _ = c.current.Write([]byte(colorReset))
+	if len(c.set) > 0 {
+		c.set = c.set[:len(c.set)-1]
+	}
+	_, _ = c.writer().Write([]byte(colorReset + c.restore()))
+}
+
+// restore returns the sequence which brings back the color of the last call to Set after a reset.
+func (c *Console) restore() string {
+	if len(c.set) == 0 {
+		return ""
+	}
+	return c.set[len(c.set)-1].start(c.profile)
 }
 
 // Write so we can treat a console as a Writer
 func (c *Console) Write(b []byte) (int, error) {
 	c.Lock()
-	n, err := c.current.Write(b)
+	n, err := c.write(b)
 	c.Unlock()
 	return n, err
 }
 
+// write writes b, which the caller must hold the lock for. While a status line is shown output is written a
+// line at a time: the status is cleared, the complete lines are written and the status is drawn again below.
+// The rest of b is held back until its line ends, otherwise the next redraw would erase it.
+func (c *Console) write(b []byte) (int, error) {
+	w, on := c.output()
+	if c.status == "" || !on {
+		if err := c.flushPending(w); err != nil {
+			return 0, err
+		}
+		return w.Write(b)
+	}
+	c.pending = append(c.pending, b...)
+	end := bytes.LastIndexByte(c.pending, '\n') + 1
+	if end == 0 {
+		return len(b), nil
+	}
+	lines := string(c.pending[:end])
+	c.pending = append([]byte(nil), c.pending[end:]...)
+	if _, err := io.WriteString(w, "\r"+clearLine+lines+c.status); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// flushPending writes the text held back while a status line was shown.
+func (c *Console) flushPending(w io.Writer) error {
+	if len(c.pending) == 0 {
+		return nil
+	}
+	_, err := w.Write(c.pending)
+	c.pending = nil
+	return err
+}
+
+// drawStatus replaces the status line with line. Nothing is drawn while the console doesn't write colors.
+func (c *Console) drawStatus(line string) {
+	c.Lock()
+	defer c.Unlock()
+	c.status = line
+	if w, on := c.output(); on {
+		_, _ = io.WriteString(w, "\r"+clearLine+line)
+	}
+}
+
+// endStatus removes the status line, writing final and a line feed in its place unless final is empty. Text
+// held back for the status line is written first, final starts a new line after it.
+func (c *Console) endStatus(final string) {
+	c.Lock()
+	defer c.Unlock()
+	c.status = ""
+	if final != "" {
+		final += lineFeed
+		if len(c.pending) > 0 {
+			final = lineFeed + final
+		}
+	}
+	w, on := c.output()
+	if on {
+		_, _ = io.WriteString(w, "\r"+clearLine)
+	}
+	if c.flushPending(w) == nil {
+		_, _ = io.WriteString(w, final)
+	}
+}
+
 // Print writes colored text to the console. The number of bytes written
 // is returned.
 func (c *Console) Print(col *Color, args ...string) (int, error) {
-	return c.Write([]byte(col.wrap(args...)))
+	c.Lock()
+	defer c.Unlock()
+	return c.write([]byte(col.wrap(c.profile, args...) + c.restore()))
 }
 
 // Printf formats according to a format specifier and writes colored text to the console.
 func (c *Console) Printf(col *Color, format string, args ...interface{}) (int, error) {
-	return c.Write([]byte(col.wrap(fmt.Sprintf(format, args...))))
+	s := fmt.Sprintf(format, args...)
+	c.Lock()
+	defer c.Unlock()
+	return c.write([]byte(col.wrap(c.profile, s) + c.restore()))
 }
 
 // Println writes colored text to console, appending input with a line feed.
 // The number of bytes written is returned.
 func (c *Console) Println(col *Color, args ...string) (int, error) {
-	s := col.wrap(args...)
-	if !strings.HasSuffix(s, lineFeed) {
-		s += lineFeed
-	}
-	return c.Write([]byte(s))
+	c.Lock()
+	defer c.Unlock()
+	return c.write([]byte(appendLineFeed(col.wrap(c.profile, args...)) + c.restore()))
+}
 
+// Sprint returns text decorated with col if the console writes colors, and plain text otherwise. Use it to
+// build strings which are written to the console later.
+func (c *Console) Sprint(col *Color, a ...interface{}) string {
+	on, p := c.policy()
+	return col.render(on, p, fmt.Sprint(a...))
+}
+
+// Sprintf formats according to a format specifier and returns text decorated with col if the console writes
+// colors.
+func (c *Console) Sprintf(col *Color, format string, a ...interface{}) string {
+	on, p := c.policy()
+	return col.render(on, p, fmt.Sprintf(format, a...))
+}
+
+// Sprintln returns text decorated with col if the console writes colors, terminated by a line feed.
+func (c *Console) Sprintln(col *Color, a ...interface{}) string {
+	on, p := c.policy()
+	return appendLineFeed(col.render(on, p, fmt.Sprint(a...)))
+}
+
+// policy returns whether the console writes colors and its profile.
+func (c *Console) policy() (bool, Profile) {
+	c.Lock()
+	defer c.Unlock()
+	_, on := c.output()
+	return on, c.profile
 }
 
 // PrintFunc returns a wrapper function for Print.
@@ -11,7 +11,8 @@ func TestConsoleWrite(t *testing.T) {
 	t.Parallel()
 	var out bytes.Buffer
 	c := Console{
-		current: &out,
+		colored:    &out,
+		noncolored: &out,
 	}
 	n, err := c.Write([]byte("foo"))
 	if err != nil {
@@ -29,7 +30,7 @@ func TestSet(t *testing.T) {
 	t.Parallel()
 	var cons Console
 	var buff bytes.Buffer
-	cons.current = &buff
+	cons.colored, cons.noncolored = &buff, &buff
 
 	col := New(FgRed)
 	cons.Set(col)
@@ -42,13 +43,32 @@ func TestUnset(t *testing.T) {
 	t.Parallel()
 	var cons Console
 	var buff bytes.Buffer
-	cons.current = &buff
+	cons.colored, cons.noncolored = &buff, &buff
 	cons.Unset()
 	if buff.String() != colorReset {
 		t.Fatalf("got %q want %q", buff.String(), colorReset)
 	}
 }
 
+func TestNestedSet(t *testing.T) {
+	t.Parallel()
+	var buff bytes.Buffer
+	cons := newMockConsole(&buff)
+	cons.Set(New(FgRed))
+	_, _ = cons.Write([]byte("a"))
+	_, _ = cons.Print(New(Bold), "b")
+	_, _ = cons.Write([]byte("c"))
+	cons.Set(New(FgBlue))
+	_, _ = cons.Write([]byte("d"))
+	cons.Unset()
+	_, _ = cons.Write([]byte("e"))
+	cons.Unset()
+	_, _ = cons.Write([]byte("f"))
+	cons.Unset()
+	want := "\x1b[31ma\x1b[1mb\x1b[0m\x1b[31mc\x1b[34md\x1b[0m\x1b[31me\x1b[0mf\x1b[0m"
+	assertEqualS(t, want, buff.String())
+}
+
 func mockStd() (*Console, func() string) {
 	r, w, _ := os.Pipe()
 	console := NewConsole(w)
@@ -75,6 +95,7 @@ func TestDisable(t *testing.T) {
 
 func TestEnable(t *testing.T) {
 	cons, outf := mockStd()
+	cons.SetMode(Always)
 	c := New(FgRed)
 	_, _ = cons.Print(c, "foo")
 	_, _ = cons.Println(c, "bar")
@@ -83,6 +104,46 @@ func TestEnable(t *testing.T) {
 	assertEqualS(t, outf(), want)
 }
 
+func TestAutoModeNotTerminal(t *testing.T) {
+	cons, outf := mockStd()
+	if cons.IsTerminal() {
+		t.Fatal("a pipe is not a terminal")
+	}
+	if cons.Mode() != Auto {
+		t.Fatalf("want %s got %s", Auto, cons.Mode())
+	}
+	_, _ = cons.Print(New(FgRed), "foo")
+	assertEqualS(t, "foo", outf())
+}
+
+func TestAutoModeForcedByEnv(t *testing.T) {
+	// can't be parallel since we're changing the environment and global state
+	restore := setenv(map[string]string{"FORCE_COLOR": "1"})
+	ReloadEnv()
+	restore()
+	defer ReloadEnv()
+	cons, outf := mockStd()
+	_, _ = cons.Print(New(FgRed), "foo")
+	assertEqualS(t, "\x1b[31mfoo\x1b[0m", outf())
+}
+
+func TestConsoleMode(t *testing.T) {
+	// can't be parallel since we're toggling global color which will interfere with concurrently running tests
+	Disable(true)
+	defer func() {
+		Disable(false)
+	}()
+	cons, outf := mockStd()
+	c := New(FgRed)
+	cons.SetMode(Always)
+	_, _ = cons.Print(c, "foo")
+	cons.SetMode(Never)
+	_, _ = cons.Print(c, "bar")
+	cons.SetMode(Auto)
+	_, _ = cons.Print(c, "baz")
+	assertEqualS(t, "\x1b[31mfoo\x1b[0mbarbaz", outf())
+}
+
 func TestConsoleEnable(t *testing.T) {
 	// can't be parallel since we're toggling global color which will interfere with concurrently running tests
 	Disable(true)
@@ -104,3 +165,78 @@ func TestFileDescriptor(t *testing.T) {
 		t.Fatalf("fd mismatch stdout %X console %X", os.Stdout.Fd(), c.Fd())
 	}
 }
+
+func TestNewConsoleWriter(t *testing.T) {
+	t.Parallel()
+	col := New(FgRed)
+
+	var stripped bytes.Buffer
+	cons := NewConsoleWriter(&stripped)
+	cons.Set(col)
+	_, _ = cons.Print(col, "foo")
+	cons.Unset()
+	assertEqualS(t, "foo", stripped.String())
+	if cons.Fd() != invalidFd {
+		t.Fatalf("expected invalid fd got %X", cons.Fd())
+	}
+
+	var passed bytes.Buffer
+	cons = NewConsoleWriter(&passed, WithMode(Always), WithProfile(ANSI))
+	_, _ = cons.Println(col, "foo")
+	_, _ = cons.Print(New(Fg256(9)), "bar")
+	cons.DisableColors(true)
+	_, _ = cons.Print(col, "baz")
+	assertEqualS(t, "\x1b[31mfoo\x1b[0m\n\x1b[91mbar\x1b[0mbaz", passed.String())
+	if cons.Mode() != Never {
+		t.Fatalf("want %s got %s", Never, cons.Mode())
+	}
+}
+
+func TestNewConsoleWriterFile(t *testing.T) {
+	t.Parallel()
+	r, w, _ := os.Pipe()
+	defer r.Close()
+	defer w.Close()
+	cons := NewConsoleWriter(w, WithMode(Never))
+	if cons.Fd() != w.Fd() {
+		t.Fatalf("fd mismatch pipe %X console %X", w.Fd(), cons.Fd())
+	}
+	if cons.Mode() != Never {
+		t.Fatalf("want %s got %s", Never, cons.Mode())
+	}
+}
+
+func TestConsoleSprint(t *testing.T) {
+	t.Parallel()
+	col := New(Bold, Fg256(208))
+	var buff bytes.Buffer
+	colored := NewConsoleWriter(&buff, WithMode(Always), WithProfile(ANSI256))
+	plain := NewConsoleWriter(&buff, WithMode(Never))
+
+	assertEqualS(t, "\x1b[1;38;5;208mfoo 1\x1b[0m", colored.Sprint(col, "foo ", 1))
+	assertEqualS(t, "\x1b[1;38;5;208mfoo 2\x1b[0m", colored.Sprintf(col, "foo %d", 2))
+	assertEqualS(t, "\x1b[1;38;5;208mfoo\x1b[0m\n", colored.Sprintln(col, "foo"))
+	colored.SetProfile(ANSI)
+	assertEqualS(t, "\x1b[1;33mfoo\x1b[0m", colored.Sprint(col, "foo"))
+
+	assertEqualS(t, "foo 1", plain.Sprint(col, "foo ", 1))
+	assertEqualS(t, "foo 2", plain.Sprintf(col, "foo %d", 2))
+	assertEqualS(t, "foo\n", plain.Sprintln(col, "foo"))
+}
+
+func TestConsolePolicyMatchesWriter(t *testing.T) {
+	// can't be parallel since we're changing the global mode
+	defer ReloadEnv()
+	var buff bytes.Buffer
+	cons := NewConsoleWriter(&buff)
+	for _, m := range []ColorMode{Auto, Always, Never} {
+		SetMode(m)
+		on, _ := cons.policy()
+		cons.Lock()
+		colored := cons.writer() == cons.colored
+		cons.Unlock()
+		if on != colored {
+			t.Fatalf("mode %s: colors %t but colored writer %t", m, on, colored)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package color
+
+import (
+	"io"
+	"strconv"
+)
+
+// Control sequences written by the cursor and screen methods of Console.
+const (
+	saveCursor     = "\x1b[s"
+	restoreCursor  = "\x1b[u"
+	hideCursor     = "\x1b[?25l"
+	showCursor     = "\x1b[?25h"
+	clearScreen    = "\x1b[2J\x1b[H"
+	enterAltScreen = "\x1b[?1049h"
+	exitAltScreen  = "\x1b[?1049l"
+)
+
+// CursorUp moves the cursor up n lines.
+func (c *Console) CursorUp(n int) { c.moveCursor(n, 'A') }
+
+// CursorDown moves the cursor down n lines.
+func (c *Console) CursorDown(n int) { c.moveCursor(n, 'B') }
+
+// CursorRight moves the cursor right n columns.
+func (c *Console) CursorRight(n int) { c.moveCursor(n, 'C') }
+
+// CursorLeft moves the cursor left n columns.
+func (c *Console) CursorLeft(n int) { c.moveCursor(n, 'D') }
+
+// SaveCursor remembers the cursor position, RestoreCursor moves the cursor back to it.
+func (c *Console) SaveCursor() { c.control(saveCursor) }
+
+// RestoreCursor moves the cursor to the position remembered by SaveCursor.
+func (c *Console) RestoreCursor() { c.control(restoreCursor) }
+
+// HideCursor makes the cursor invisible until ShowCursor is called.
+func (c *Console) HideCursor() { c.control(hideCursor) }
+
+// ShowCursor makes the cursor visible again.
+func (c *Console) ShowCursor() { c.control(showCursor) }
+
+// ClearLine erases the line the cursor is on and moves the cursor to its start.
+func (c *Console) ClearLine() { c.control("\r" + clearLine) }
+
+// ClearScreen erases the screen and moves the cursor to the top left corner.
+func (c *Console) ClearScreen() { c.control(clearScreen) }
+
+// EnterAltScreen switches to the alternate screen buffer used by full screen programs. The previous content
+// of the terminal comes back with ExitAltScreen.
+func (c *Console) EnterAltScreen() { c.control(enterAltScreen) }
+
+// ExitAltScreen switches back to the main screen buffer.
+func (c *Console) ExitAltScreen() { c.control(exitAltScreen) }
+
+func (c *Console) moveCursor(n int, dir byte) {
+	if n <= 0 {
+		return
+	}
+	c.control(escape + strconv.Itoa(n) + string(dir))
+}
+
+// control writes a control sequence. Control sequences are meant for terminals, other outputs such as pipes
+// and log files only get them in Always mode. Unlike colors they aren't stripped in Never mode, so they go to
+// the colored writer which translates them on Windows consoles without ANSI support. An active status line is
+// cleared first and drawn again after the sequence.
+func (c *Console) control(seq string) {
+	c.Lock()
+	defer c.Unlock()
+	if !c.terminal && c.resolvedMode() != Always {
+		return
+	}
+	if _, on := c.output(); on && c.status != "" {
+		_, _ = io.WriteString(c.colored, "\r"+clearLine+seq+c.status)
+		return
+	}
+	if c.flushPending(c.colored) == nil {
+		_, _ = io.WriteString(c.colored, seq)
+	}
+}
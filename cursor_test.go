@@ -0,0 +1,64 @@
+package color
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCursorControl(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		name string
+		call func(c *Console)
+		want string
+	}{
+		{"up", func(c *Console) { c.CursorUp(2) }, "\x1b[2A"},
+		{"down", func(c *Console) { c.CursorDown(1) }, "\x1b[1B"},
+		{"right", func(c *Console) { c.CursorRight(10) }, "\x1b[10C"},
+		{"left", func(c *Console) { c.CursorLeft(3) }, "\x1b[3D"},
+		{"no movement", func(c *Console) { c.CursorUp(0) }, ""},
+		{"save restore", func(c *Console) { c.SaveCursor(); c.RestoreCursor() }, "\x1b[s\x1b[u"},
+		{"hide show", func(c *Console) { c.HideCursor(); c.ShowCursor() }, "\x1b[?25l\x1b[?25h"},
+		{"clear line", func(c *Console) { c.ClearLine() }, "\r\x1b[2K"},
+		{"clear screen", func(c *Console) { c.ClearScreen() }, "\x1b[2J\x1b[H"},
+		{"alternate screen", func(c *Console) { c.EnterAltScreen(); c.ExitAltScreen() }, "\x1b[?1049h\x1b[?1049l"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, m := range []ColorMode{Always, Never} {
+				var buff bytes.Buffer
+				cons := NewConsoleWriter(&buff, WithMode(m))
+				cons.terminal = true
+				tc.call(cons)
+				assertEqualS(t, tc.want, buff.String())
+			}
+		})
+	}
+}
+
+func TestCursorControlNotTerminal(t *testing.T) {
+	t.Parallel()
+	var buff bytes.Buffer
+	cons := NewConsoleWriter(&buff, WithMode(Never))
+	cons.HideCursor()
+	cons.EnterAltScreen()
+	assertEqualS(t, "", buff.String())
+
+	cons.SetMode(Always)
+	cons.HideCursor()
+	assertEqualS(t, hideCursor, buff.String())
+}
+
+func TestCursorControlStatus(t *testing.T) {
+	t.Parallel()
+	var buff bytes.Buffer
+	cons := newTerminalConsole(&buff)
+	cons.drawStatus("status")
+	_, _ = cons.Write([]byte("partial"))
+	cons.ClearLine()
+	cons.endStatus("")
+	want := "\r\x1b[2Kstatus" +
+		"\r\x1b[2K\r\x1b[2Kstatus" +
+		"\r\x1b[2Kpartial"
+	assertEqualS(t, want, buff.String())
+}
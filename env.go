@@ -0,0 +1,89 @@
+package color
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// The package honors the following environment variables, in order of precedence:
+//
+//	FORCE_COLOR     0 or false disables colors, any other value enables them. A level of 1, 2 or 3 also
+//	                raises the profile to at least ANSI, ANSI256 or TrueColor.
+//	CLICOLOR_FORCE  any value other than 0 enables colors.
+//	NO_COLOR        any non empty value disables colors.
+//	CLICOLOR        0 disables colors.
+//
+// The environment sets the initial global mode: Never when colors are disabled, Always when they're forced
+// and Auto otherwise. Calling SetMode overrides it and Console.SetMode overrides both for a single console.
+
+// envSetting is the preference for colored output expressed by the environment.
+type envSetting int
+
+const (
+	envUnset envSetting = iota
+	envNever
+	envAlways
+)
+
+// ReloadEnv reads the color environment variables again, replacing any earlier call to SetMode or
+// SetProfile. It is mostly useful in tests.
+func ReloadEnv() {
+	SetProfile(DetectProfile())
+	SetMode(envMode())
+}
+
+func init() {
+	SetMode(envMode())
+	SetProfile(DetectProfile())
+}
+
+// envMode returns the global mode requested by the environment.
+func envMode() ColorMode {
+	switch readEnv(os.LookupEnv) {
+	case envNever:
+		return Never
+	case envAlways:
+		return Always
+	}
+	return Auto
+}
+
+func readEnv(lookup func(string) (string, bool)) envSetting {
+	if v, ok := lookup("FORCE_COLOR"); ok {
+		if _, ok := forceColorLevel(v); ok {
+			return envAlways
+		}
+		return envNever
+	}
+	if v, ok := lookup("CLICOLOR_FORCE"); ok && v != "" && v != "0" {
+		return envAlways
+	}
+	if v, ok := lookup("NO_COLOR"); ok && v != "" {
+		return envNever
+	}
+	if v, ok := lookup("CLICOLOR"); ok && v == "0" {
+		return envNever
+	}
+	return envUnset
+}
+
+// forceColorLevel interprets the value of FORCE_COLOR. It returns false when colors are turned off and the
+// profile requested otherwise. Values which don't name a level request ANSI.
+func forceColorLevel(v string) (Profile, bool) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	switch v {
+	case "0", "false":
+		return ANSI, false
+	case "", "1", "true":
+		return ANSI, true
+	}
+	n, err := strconv.Atoi(v)
+	switch {
+	case err != nil, n < 2:
+		return ANSI, true
+	case n == 2:
+		return ANSI256, true
+	}
+	return TrueColor, true
+}
@@ -0,0 +1,142 @@
+package color
+
+import (
+	"os"
+	"testing"
+)
+
+// detectionEnv lists the environment variables read to detect color support.
+var detectionEnv = []string{
+	"NO_COLOR", "FORCE_COLOR", "CLICOLOR", "CLICOLOR_FORCE", "COLORTERM", "TERM", "TERM_PROGRAM", "WT_SESSION",
+	"FORCE_HYPERLINK", "VTE_VERSION", "KONSOLE_VERSION",
+}
+
+// TestMain clears the variables detecting color support, so the tests don't depend on the environment of
+// the developer running them.
+func TestMain(m *testing.M) {
+	for _, k := range detectionEnv {
+		_ = os.Unsetenv(k)
+	}
+	ReloadEnv()
+	os.Exit(m.Run())
+}
+
+func lookupMap(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+// setenv sets the environment variables in env and returns a function restoring their previous values.
+func setenv(env map[string]string) func() {
+	type saved struct {
+		value string
+		ok    bool
+	}
+	old := make(map[string]saved, len(env))
+	for k, v := range env {
+		prev, ok := os.LookupEnv(k)
+		old[k] = saved{prev, ok}
+		_ = os.Setenv(k, v)
+	}
+	return func() {
+		for k, s := range old {
+			if s.ok {
+				_ = os.Setenv(k, s.value)
+				continue
+			}
+			_ = os.Unsetenv(k)
+		}
+	}
+}
+
+func TestReadEnv(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		name string
+		env  map[string]string
+		want envSetting
+	}{
+		{"empty", map[string]string{}, envUnset},
+		{"no color", map[string]string{"NO_COLOR": "1"}, envNever},
+		{"no color empty", map[string]string{"NO_COLOR": ""}, envUnset},
+		{"force color", map[string]string{"FORCE_COLOR": "1"}, envAlways},
+		{"force color empty", map[string]string{"FORCE_COLOR": ""}, envAlways},
+		{"force color 0", map[string]string{"FORCE_COLOR": "0"}, envNever},
+		{"force color false", map[string]string{"FORCE_COLOR": "false"}, envNever},
+		{"force color beats no color", map[string]string{"FORCE_COLOR": "3", "NO_COLOR": "1"}, envAlways},
+		{"force color 0 beats clicolor force",
+			map[string]string{"FORCE_COLOR": "0", "CLICOLOR_FORCE": "1"}, envNever},
+		{"clicolor force", map[string]string{"CLICOLOR_FORCE": "1"}, envAlways},
+		{"clicolor force 0", map[string]string{"CLICOLOR_FORCE": "0"}, envUnset},
+		{"clicolor force beats no color", map[string]string{"CLICOLOR_FORCE": "1", "NO_COLOR": "1"}, envAlways},
+		{"clicolor 0", map[string]string{"CLICOLOR": "0"}, envNever},
+		{"clicolor 1", map[string]string{"CLICOLOR": "1"}, envUnset},
+		{"no color beats clicolor", map[string]string{"CLICOLOR": "1", "NO_COLOR": "1"}, envNever},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := readEnv(lookupMap(tc.env)); got != tc.want {
+				t.Fatalf("want %d got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestForceColorProfile(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		name string
+		env  map[string]string
+		want Profile
+	}{
+		{"level 1", map[string]string{"FORCE_COLOR": "1"}, ANSI},
+		{"level 2", map[string]string{"FORCE_COLOR": "2"}, ANSI256},
+		{"level 3", map[string]string{"FORCE_COLOR": "3"}, TrueColor},
+		{"level with spaces", map[string]string{"FORCE_COLOR": " 3 "}, TrueColor},
+		{"level is a minimum", map[string]string{"FORCE_COLOR": "1", "COLORTERM": "truecolor"}, TrueColor},
+		{"level raises terminal", map[string]string{"FORCE_COLOR": "3", "TERM": "xterm-256color"}, TrueColor},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got := detectProfile(func(key string) string {
+				return tc.env[key]
+			})
+			if got != tc.want {
+				t.Fatalf("want %s got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestReloadEnv(t *testing.T) {
+	// can't be parallel since we're changing the environment and global state
+	oldMode, oldProfile := Mode(), ActiveProfile()
+	defer func() {
+		SetMode(oldMode)
+		SetProfile(oldProfile)
+	}()
+
+	restore := setenv(map[string]string{"NO_COLOR": "1"})
+	ReloadEnv()
+	restore()
+	if Enabled() {
+		t.Fatal("expected NO_COLOR to disable colors")
+	}
+
+	Disable(false)
+	if !Enabled() {
+		t.Fatal("expected Disable to override the environment")
+	}
+
+	restore = setenv(map[string]string{"FORCE_COLOR": "2", "NO_COLOR": "1", "COLORTERM": "", "TERM": "xterm"})
+	ReloadEnv()
+	restore()
+	if !Enabled() {
+		t.Fatal("expected FORCE_COLOR to enable colors")
+	}
+	if ActiveProfile() != ANSI256 {
+		t.Fatalf("want %s got %s", ANSI256, ActiveProfile())
+	}
+}
@@ -0,0 +1,87 @@
+package color
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Colored is a value printed with a Color. It implements fmt.Formatter: width, precision and flags are
+// applied to the visible text before the escape sequences are added, so
+//
+//	fmt.Printf("%-10s|", red.Wrap("ok"))
+//
+// pads "ok" to ten columns no matter how long the escape sequences are. Padding is measured with
+// VisibleWidth and is part of the colored text.
+type Colored struct {
+	color   Color
+	value   interface{}
+	console *Console
+}
+
+// Wrap returns value as a Colored, which is printed with the color when colors are enabled globally.
+func (v Color) Wrap(value interface{}) Colored {
+	return Colored{color: v, value: value}
+}
+
+// Wrap returns value as a Colored which is printed with col when the console writes colors, using the
+// profile of the console.
+func (c *Console) Wrap(col *Color, value interface{}) Colored {
+	return Colored{color: *col, value: value, console: c}
+}
+
+// String returns the value formatted with %v and decorated with the color.
+func (c Colored) String() string {
+	return fmt.Sprint(c)
+}
+
+// Format implements fmt.Formatter.
+func (c Colored) Format(f fmt.State, verb rune) {
+	width, hasWidth := f.Width()
+	var text string
+	if hasWidth && f.Flag('0') && !f.Flag('-') {
+		// zero padding goes between the sign and the digits, fmt knows where that is
+		text = fmt.Sprintf(directive(f, verb, true), c.value)
+	} else {
+		text = fmt.Sprintf(directive(f, verb, false), c.value)
+		if pad := width - VisibleWidth(text); hasWidth && pad > 0 {
+			if f.Flag('-') {
+				text += strings.Repeat(" ", pad)
+			} else {
+				text = strings.Repeat(" ", pad) + text
+			}
+		}
+	}
+	on, p := c.policy()
+	_, _ = io.WriteString(f, c.color.render(on, p, text))
+}
+
+// policy returns whether colors are written and the profile, from the console if there is one.
+func (c Colored) policy() (bool, Profile) {
+	if c.console != nil {
+		return c.console.policy()
+	}
+	return Enabled(), ActiveProfile()
+}
+
+// directive rebuilds the format directive for verb from the flags and precision in f, and the width if
+// withWidth is true.
+func directive(f fmt.State, verb rune, withWidth bool) string {
+	var b strings.Builder
+	b.WriteByte('%')
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			b.WriteRune(flag)
+		}
+	}
+	if w, ok := f.Width(); ok && withWidth {
+		b.WriteString(strconv.Itoa(w))
+	}
+	if p, ok := f.Precision(); ok {
+		b.WriteByte('.')
+		b.WriteString(strconv.Itoa(p))
+	}
+	b.WriteRune(verb)
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package color
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestColoredFormat(t *testing.T) {
+	t.Parallel()
+	cons := newMockConsole(&bytes.Buffer{})
+	cons.SetMode(Always)
+	red := New(FgRed)
+	tt := []struct {
+		name   string
+		format string
+		value  interface{}
+		want   string
+	}{
+		{"plain", "%v", "ok", "\x1b[31mok\x1b[0m"},
+		{"left aligned", "%-6s|", "ok", "\x1b[31mok    \x1b[0m|"},
+		{"right aligned", "%6s|", "ok", "\x1b[31m    ok\x1b[0m|"},
+		{"precision", "%.2s", "hello", "\x1b[31mhe\x1b[0m"},
+		{"float", "%8.3f", 3.14159, "\x1b[31m   3.142\x1b[0m"},
+		{"zero padding", "%+05d", 42, "\x1b[31m+0042\x1b[0m"},
+		{"quoted", "%q", "a", "\x1b[31m\"a\"\x1b[0m"},
+		{"wide runes", "%-4s|", "日本", "\x1b[31m日本\x1b[0m|"},
+		{"colored value", "%-4s|", New(Bold).wrap(TrueColor, "ab"), "\x1b[31m\x1b[1mab\x1b[0m\x1b[31m  \x1b[0m|"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assertEqualS(t, tc.want, fmt.Sprintf(tc.format, cons.Wrap(red, tc.value)))
+		})
+	}
+}
+
+func TestColoredDisabled(t *testing.T) {
+	t.Parallel()
+	cons := newMockConsole(&bytes.Buffer{})
+	cons.SetMode(Never)
+	c := cons.Wrap(New(FgGreen), "ok")
+	assertEqualS(t, "ok    |", fmt.Sprintf("%-6s|", c))
+	assertEqualS(t, "ok", c.String())
+
+	cons.SetMode(Always)
+	cons.SetProfile(ANSI)
+	assertEqualS(t, "\x1b[33mok\x1b[0m", fmt.Sprint(cons.Wrap(New(Hex("#cdcd00")), "ok")))
+}
+
+// can't be parallel since it changes the global mode
+func TestColorWrap(t *testing.T) {
+	defer ReloadEnv()
+	c := New(FgBlue).Wrap(7)
+	SetMode(Never)
+	assertEqualS(t, "  7", fmt.Sprintf("%3d", c))
+	SetMode(Always)
+	SetProfile(TrueColor)
+	assertEqualS(t, "\x1b[34m  7\x1b[0m", fmt.Sprintf("%3d", c))
+}
@@ -2,4 +2,7 @@ module github.com/heroku/color
 
 go 1.13
 
-require github.com/mattn/go-colorable v0.1.2
+require (
+	github.com/mattn/go-colorable v0.1.2
+	github.com/mattn/go-isatty v0.0.8
+)
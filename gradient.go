@@ -0,0 +1,118 @@
+package color
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Gradient blends two or more colors. The colors in between are interpolated in the CIELAB color space, which
+// avoids the muddy middle of a blend in RGB. On consoles which can't show 24 bit colors each color is
+// converted to the nearest supported one, runs of text ending up with the same color share an escape
+// sequence.
+type Gradient struct {
+	stops []lab
+}
+
+// NewGradient returns a gradient through the colors stops, spaced evenly. Stops may be any foreground or
+// background color, attributes which aren't colors are skipped. A gradient with a single color is solid,
+// one without colors leaves text unchanged.
+func NewGradient(stops ...Attribute) Gradient {
+	var g Gradient
+	for _, a := range stops {
+		if r, gr, b, ok := attributeRGB(a); ok {
+			g.stops = append(g.stops, rgbToLab(r, gr, b))
+		}
+	}
+	return g
+}
+
+// At returns the foreground color at t, from 0 at the first color to 1 at the last.
+func (g Gradient) At(t float64) Attribute {
+	if len(g.stops) == 0 {
+		return 0
+	}
+	t = clamp01(t)
+	segments := len(g.stops) - 1
+	i := int(t * float64(segments))
+	if i >= segments {
+		return FgRGB(g.stops[segments].rgb())
+	}
+	return FgRGB(g.stops[i].mix(g.stops[i+1], t*float64(segments)-float64(i)).rgb())
+}
+
+// Sprint colors the characters of s from left to right with the gradient, when colors are enabled globally.
+// Escape sequences in s are removed.
+func (g Gradient) Sprint(s string) string {
+	return g.horizontal(Enabled(), ActiveProfile(), s)
+}
+
+// SprintLines colors each line of s with one color of the gradient, from the first line to the last, when
+// colors are enabled globally. Escape sequences in s are removed.
+func (g Gradient) SprintLines(s string) string {
+	return g.vertical(Enabled(), ActiveProfile(), s)
+}
+
+// SprintGradient colors the characters of s from left to right with g if the console writes colors.
+func (c *Console) SprintGradient(g Gradient, s string) string {
+	on, p := c.policy()
+	return g.horizontal(on, p, s)
+}
+
+// SprintGradientLines colors each line of s with one color of g if the console writes colors.
+func (c *Console) SprintGradientLines(g Gradient, s string) string {
+	on, p := c.policy()
+	return g.vertical(on, p, s)
+}
+
+// horizontal colors the visible characters of s in turn for profile p.
+func (g Gradient) horizontal(on bool, p Profile, s string) string {
+	s = Strip(s)
+	if !on || len(g.stops) == 0 {
+		return s
+	}
+	runes := []rune(s)
+	var b strings.Builder
+	var current Attribute
+	for i, r := range runes {
+		if !unicode.IsSpace(r) {
+			if a := downsampleAttribute(g.At(position(i, len(runes))), p); a != current {
+				current = a
+				b.WriteString(escape + a.String() + endCode)
+			}
+		}
+		b.WriteRune(r)
+	}
+	if current != 0 {
+		b.WriteString(colorReset)
+	}
+	return b.String()
+}
+
+// vertical colors the lines of s for profile p, empty lines are left alone.
+func (g Gradient) vertical(on bool, p Profile, s string) string {
+	s = Strip(s)
+	if !on || len(g.stops) == 0 {
+		return s
+	}
+	lines := strings.Split(s, lineFeed)
+	// a final line feed doesn't start another line
+	n := len(lines)
+	if lines[n-1] == "" {
+		n--
+	}
+	for i := 0; i < n; i++ {
+		if lines[i] != "" {
+			a := downsampleAttribute(g.At(position(i, n)), p)
+			lines[i] = escape + a.String() + endCode + lines[i] + colorReset
+		}
+	}
+	return strings.Join(lines, lineFeed)
+}
+
+// position returns where item i of n lies between 0 and 1.
+func position(i, n int) float64 {
+	if n <= 1 {
+		return 0
+	}
+	return float64(i) / float64(n-1)
+}
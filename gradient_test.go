@@ -0,0 +1,73 @@
+package color
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestLabRoundTrip(t *testing.T) {
+	t.Parallel()
+	for _, c := range [][3]uint8{{0, 0, 0}, {255, 255, 255}, {255, 136, 0}, {18, 52, 86}, {1, 2, 3}} {
+		r, g, b := rgbToLab(c[0], c[1], c[2]).rgb()
+		if r != c[0] || g != c[1] || b != c[2] {
+			t.Fatalf("want %v got %v", c, [3]uint8{r, g, b})
+		}
+	}
+	if l := rgbToLab(255, 255, 255); l.l < 99.9 || l.a > 0.01 || l.b > 0.01 {
+		t.Fatalf("expected white to have L=100 got %+v", l)
+	}
+}
+
+func TestGradientAt(t *testing.T) {
+	t.Parallel()
+	g := NewGradient(Hex("#ff0000"), Bold, FgBlue, Hex("#ffffff"))
+	tt := []struct {
+		at   float64
+		want Attribute
+	}{
+		{-1, FgRGB(255, 0, 0)},
+		{0, FgRGB(255, 0, 0)},
+		{0.5, FgRGB(0, 0, 238)},
+		{1, FgRGB(255, 255, 255)},
+		{2, FgRGB(255, 255, 255)},
+		{math.NaN(), FgRGB(255, 0, 0)},
+	}
+	for _, tc := range tt {
+		if got := g.At(tc.at); got != tc.want {
+			t.Fatalf("at %v want %s got %s", tc.at, tc.want.Name(), got.Name())
+		}
+	}
+	// a perceptual blend of red and blue is a purple brighter than the RGB average
+	mid := NewGradient(Hex("#ff0000"), Hex("#0000ff")).At(0.5)
+	if r, g, b := mid.rgb(); r <= 128 || g != 0 || b <= 128 {
+		t.Fatalf("unexpected middle color %s", mid.Name())
+	}
+	if NewGradient().At(0.5) != 0 {
+		t.Fatal("expected an empty gradient to have no color")
+	}
+}
+
+func TestGradientHorizontal(t *testing.T) {
+	t.Parallel()
+	g := NewGradient(Hex("#000000"), Hex("#ffffff"))
+	want := "\x1b[38;2;0;0;0ma\x1b[38;2;78;78;78mb \x1b[38;2;255;255;255mc\x1b[0m"
+	assertEqualS(t, want, g.horizontal(true, TrueColor, "ab c"))
+	assertEqualS(t, "ab c", g.horizontal(false, TrueColor, "\x1b[1mab c"))
+
+	// colors which downsample to the same basic color share an escape sequence
+	red := NewGradient(Hex("#ff0000"), Hex("#ee1111"))
+	assertEqualS(t, "\x1b[91mhello\x1b[0m", red.horizontal(true, ANSI, "hello"))
+	orange := NewGradient(Hex("#ff0000"), Hex("#ffaf00"))
+	if got := orange.horizontal(true, ANSI256, "hello"); strings.Count(got, "\x1b[38;5;") < 3 {
+		t.Fatalf("expected several 256 colors got %q", got)
+	}
+}
+
+func TestGradientVertical(t *testing.T) {
+	t.Parallel()
+	g := NewGradient(FgRed, FgBlue)
+	want := "\x1b[38;2;205;0;0mone\x1b[0m\n\n\x1b[38;2;0;0;238mthree\x1b[0m\n"
+	assertEqualS(t, want, g.vertical(true, TrueColor, "one\n\nthree\n"))
+	assertEqualS(t, "\x1b[38;2;205;0;0msolo\x1b[0m", g.vertical(true, TrueColor, "solo"))
+}
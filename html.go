@@ -0,0 +1,179 @@
+package color
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// HTMLRenderer converts colored text to HTML. Every run of styled text becomes a <span> element which is
+// closed when the style changes or is reset, the text itself is escaped. Line feeds are kept, so the result
+// is usually placed in a <pre> element. The zero value uses inline styles.
+type HTMLRenderer struct {
+	// Classes selects CSS classes such as "bold fg-red" instead of inline styles. 24 bit colors don't have a
+	// class and are always written as inline styles.
+	Classes bool
+	// ClassPrefix is prepended to each class name, for example "ansi-".
+	ClassPrefix string
+}
+
+// HTML converts text containing SGR codes, such as the output of Color.Sprint, to HTML with inline styles.
+func HTML(s string) string {
+	return HTMLRenderer{}.Render(s)
+}
+
+// Render converts text containing SGR codes to HTML.
+func (r HTMLRenderer) Render(s string) string {
+	return r.RenderSpans(Parse(s))
+}
+
+// RenderSpans converts spans to HTML.
+func (r HTMLRenderer) RenderSpans(spans []Span) string {
+	var b strings.Builder
+	for _, span := range spans {
+		text := html.EscapeString(span.Text)
+		if span.Style.IsZero() {
+			b.WriteString(text)
+			continue
+		}
+		b.WriteString("<span")
+		classes, styles := r.attributes(span.Style)
+		if len(classes) > 0 {
+			b.WriteString(` class="`)
+			b.WriteString(strings.Join(classes, " "))
+			b.WriteString(`"`)
+		}
+		if len(styles) > 0 {
+			b.WriteString(` style="`)
+			b.WriteString(strings.Join(styles, "; "))
+			b.WriteString(`"`)
+		}
+		b.WriteString(">")
+		b.WriteString(text)
+		b.WriteString("</span>")
+	}
+	return b.String()
+}
+
+// attributes returns the CSS classes and inline style declarations for s.
+func (r HTMLRenderer) attributes(s Style) (classes, styles []string) {
+	fg, bg := s.fg, s.bg
+	if s.Has(ReverseVideo) {
+		fg, bg = bg, fg
+	}
+	if r.Classes {
+		for _, d := range htmlDecorations {
+			if s.Has(d.attr) {
+				classes = append(classes, r.ClassPrefix+d.class)
+			}
+		}
+		if c, ok := colorClass("fg", fg); ok {
+			classes = append(classes, r.ClassPrefix+c)
+		} else if fg != 0 {
+			styles = append(styles, "color: "+cssColor(fg))
+		}
+		if c, ok := colorClass("bg", bg); ok {
+			classes = append(classes, r.ClassPrefix+c)
+		} else if bg != 0 {
+			styles = append(styles, "background-color: "+cssColor(bg))
+		}
+		if s.ul != 0 {
+			styles = append(styles, "text-decoration-color: "+cssColor(s.ul))
+		}
+		return classes, styles
+	}
+
+	if fg != 0 {
+		styles = append(styles, "color: "+cssColor(fg))
+	}
+	if bg != 0 {
+		styles = append(styles, "background-color: "+cssColor(bg))
+	}
+	if s.Has(Bold) {
+		styles = append(styles, "font-weight: bold")
+	}
+	if s.Has(Faint) {
+		styles = append(styles, "opacity: 0.5")
+	}
+	if s.Has(Italic) {
+		styles = append(styles, "font-style: italic")
+	}
+	var lines []string
+	if s.decorations&underlines != 0 {
+		lines = append(lines, "underline")
+	}
+	if s.Has(Overline) {
+		lines = append(lines, "overline")
+	}
+	if s.Has(CrossedOut) {
+		lines = append(lines, "line-through")
+	}
+	if len(lines) > 0 {
+		styles = append(styles, "text-decoration: "+strings.Join(lines, " "))
+	}
+	switch {
+	case s.Has(DoubleUnderline):
+		styles = append(styles, "text-decoration-style: double")
+	case s.Has(CurlyUnderline):
+		styles = append(styles, "text-decoration-style: wavy")
+	case s.Has(DottedUnderline):
+		styles = append(styles, "text-decoration-style: dotted")
+	}
+	if s.ul != 0 {
+		styles = append(styles, "text-decoration-color: "+cssColor(s.ul))
+	}
+	if s.Has(Concealed) {
+		styles = append(styles, "visibility: hidden")
+	}
+	return classes, styles
+}
+
+// htmlDecorations maps decorations to class names in the order the classes are written.
+var htmlDecorations = []struct {
+	attr  Attribute
+	class string
+}{
+	{Bold, "bold"},
+	{Faint, "faint"},
+	{Italic, "italic"},
+	{Underline, "underline"},
+	{DoubleUnderline, "double-underline"},
+	{CurlyUnderline, "curly-underline"},
+	{DottedUnderline, "dotted-underline"},
+	{BlinkSlow, "blink"},
+	{BlinkRapid, "blink-rapid"},
+	{ReverseVideo, "reverse"},
+	{Concealed, "conceal"},
+	{CrossedOut, "crossed-out"},
+	{Overline, "overline"},
+}
+
+var colorNames = [8]string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// colorClass returns the class for color a, for example fg-red, fg-bright-red or fg-208. 24 bit colors don't
+// have a class.
+func colorClass(layer string, a Attribute) (string, bool) {
+	if a == 0 {
+		return "", false
+	}
+	if a.isExtended() {
+		if a.kind() == kindFg256 || a.kind() == kindBg256 {
+			return fmt.Sprintf("%s-%d", layer, a.value()), true
+		}
+		return "", false
+	}
+	i := paletteIndex(ansiForeground, a)
+	if i < 0 {
+		i = paletteIndex(ansiBackground, a)
+	}
+	if i < 8 {
+		return layer + "-" + colorNames[i], true
+	}
+	return layer + "-bright-" + colorNames[i-8], true
+}
+
+// cssColor returns color a in CSS hex notation.
+func cssColor(a Attribute) string {
+	r, g, b, _ := attributeRGB(a)
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
@@ -0,0 +1,57 @@
+package color
+
+import "testing"
+
+func TestHTML(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "a < b & c", "a &lt; b &amp; c"},
+		{"red", "\x1b[31mred\x1b[0m done", `<span style="color: #cd0000">red</span> done`},
+		{"escaped", "\x1b[1m<b>\x1b[0m", `<span style="font-weight: bold">&lt;b&gt;</span>`},
+		{"cumulative", "\x1b[1m\x1b[4ma\x1b[24mb",
+			`<span style="font-weight: bold; text-decoration: underline">a</span>` +
+				`<span style="font-weight: bold">b</span>`},
+		{"256 and rgb", "\x1b[38;5;208;48;2;1;2;3mx",
+			`<span style="color: #ff8700; background-color: #010203">x</span>`},
+		{"reverse", "\x1b[7;31;47mx", `<span style="color: #e5e5e5; background-color: #cd0000">x</span>`},
+		{"decorations", "\x1b[2;3;8;9mx", `<span style="opacity: 0.5; font-style: italic; ` +
+			`text-decoration: line-through; visibility: hidden">x</span>`},
+		{"underline styles", "\x1b[4:3;53;58;5;196mx", `<span style="text-decoration: underline overline; ` +
+			`text-decoration-style: wavy; text-decoration-color: #ff0000">x</span>`},
+		{"newlines kept", "\x1b[32mok\n\x1b[0mnext\n", "<span style=\"color: #00cd00\">ok\n</span>next\n"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assertEqualS(t, tc.want, HTML(tc.in))
+		})
+	}
+}
+
+func TestHTMLClasses(t *testing.T) {
+	t.Parallel()
+	r := HTMLRenderer{Classes: true, ClassPrefix: "ansi-"}
+	tt := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"basic", "\x1b[1;31;44mx", `<span class="ansi-bold ansi-fg-red ansi-bg-blue">x</span>`},
+		{"bright", "\x1b[93;101mx", `<span class="ansi-fg-bright-yellow ansi-bg-bright-red">x</span>`},
+		{"256", "\x1b[4;38;5;208mx", `<span class="ansi-underline ansi-fg-208">x</span>`},
+		{"double underline", "\x1b[21;53;58;2;1;2;3mx", `<span class="ansi-double-underline ansi-overline" ` +
+			`style="text-decoration-color: #010203">x</span>`},
+		{"rgb inline", "\x1b[38;2;255;136;0mx", `<span style="color: #ff8800">x</span>`},
+		{"reverse", "\x1b[7;32mx", `<span class="ansi-reverse ansi-bg-green">x</span>`},
+		{"color output", New(Italic, FgCyan).wrap(TrueColor, "c"),
+			`<span class="ansi-italic ansi-fg-cyan">c</span>`},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assertEqualS(t, tc.want, r.Render(tc.in))
+		})
+	}
+}
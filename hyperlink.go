@@ -0,0 +1,74 @@
+package color
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Hyperlink returns text linked to url for standard out, see Console.Hyperlink.
+func Hyperlink(url, text string) string {
+	return Stdout().Hyperlink(url, text)
+}
+
+// Hyperlink returns text linked to url with an OSC 8 sequence, which terminals supporting it show as a
+// clickable link. Other consoles, including log files, get "text (url)", or just the url when text is empty
+// or the url itself. The result can be colored like any other text:
+//
+//	c.Println(color.New(color.Underline, color.FgBlue), c.Hyperlink(url, "dashboard"))
+func (c *Console) Hyperlink(url, text string) string {
+	url = strings.Map(dropControl, url)
+	c.Lock()
+	_, colored := c.output()
+	on := c.hyperlinks && colored
+	c.Unlock()
+	if on {
+		if text == "" {
+			text = url
+		}
+		return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+	}
+	if text == "" || text == url {
+		return url
+	}
+	return text + " (" + url + ")"
+}
+
+// dropControl removes control characters which could end the OSC 8 sequence early.
+func dropControl(r rune) rune {
+	if r < 0x20 || r == 0x7f {
+		return -1
+	}
+	return r
+}
+
+// WithHyperlinks turns OSC 8 hyperlinks on or off for a new Console instead of detecting whether the terminal
+// supports them.
+func WithHyperlinks(on bool) ConsoleOption {
+	return func(c *Console) {
+		c.hyperlinks = on
+	}
+}
+
+// detectHyperlinks reports whether a console writes hyperlinks. FORCE_HYPERLINK set to a number turns them on
+// when it isn't 0 and off when it is. Otherwise terminals known to support OSC 8 are recognized from the
+// environment.
+func detectHyperlinks(getenv func(string) string, terminal bool) bool {
+	if v, err := strconv.Atoi(getenv("FORCE_HYPERLINK")); err == nil {
+		return v != 0
+	}
+	if !terminal {
+		return false
+	}
+	if v, err := strconv.Atoi(getenv("VTE_VERSION")); err == nil && v >= 5000 {
+		return true
+	}
+	switch getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "Hyper", "ghostty":
+		return true
+	}
+	switch getenv("TERM") {
+	case "xterm-kitty", "xterm-ghostty", "alacritty", "foot", "wezterm":
+		return true
+	}
+	return getenv("WT_SESSION") != "" || getenv("KONSOLE_VERSION") != ""
+}
@@ -0,0 +1,61 @@
+package color
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHyperlink(t *testing.T) {
+	t.Parallel()
+	var buff bytes.Buffer
+	cons := NewConsoleWriter(&buff, WithMode(Always), WithHyperlinks(true))
+	url := "https://dashboard.heroku.com"
+	assertEqualS(t, "\x1b]8;;"+url+"\x1b\\Dashboard\x1b]8;;\x1b\\", cons.Hyperlink(url, "Dashboard"))
+	assertEqualS(t, "\x1b]8;;"+url+"\x1b\\"+url+"\x1b]8;;\x1b\\", cons.Hyperlink(url, ""))
+	assertEqualS(t, "\x1b]8;;https://x/\\\x1b\\x\x1b]8;;\x1b\\", cons.Hyperlink("https://x/\x1b\\\x07", "x"))
+
+	_, _ = cons.Print(New(FgBlue), cons.Hyperlink(url, "Dashboard"))
+	assertEqualS(t, "\x1b[34m\x1b]8;;"+url+"\x1b\\Dashboard\x1b]8;;\x1b\\\x1b[0m", buff.String())
+	assertEqualS(t, "Dashboard", Strip(buff.String()))
+	if w := VisibleWidth(buff.String()); w != 9 {
+		t.Fatalf("expected the link to take 9 columns got %d", w)
+	}
+
+	cons.SetMode(Never)
+	assertEqualS(t, "Dashboard ("+url+")", cons.Hyperlink(url, "Dashboard"))
+	assertEqualS(t, url, cons.Hyperlink(url, url))
+
+	plain := NewConsoleWriter(&buff, WithMode(Always), WithHyperlinks(false))
+	got := plain.Hyperlink("https://devcenter.heroku.com", "docs")
+	assertEqualS(t, "docs (https://devcenter.heroku.com)", got)
+}
+
+func TestDetectHyperlinks(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		name     string
+		env      map[string]string
+		terminal bool
+		want     bool
+	}{
+		{"plain terminal", map[string]string{"TERM": "xterm-256color"}, true, false},
+		{"iterm", map[string]string{"TERM_PROGRAM": "iTerm.app"}, true, true},
+		{"kitty", map[string]string{"TERM": "xterm-kitty"}, true, true},
+		{"vte", map[string]string{"VTE_VERSION": "6003"}, true, true},
+		{"old vte", map[string]string{"VTE_VERSION": "4802"}, true, false},
+		{"windows terminal", map[string]string{"WT_SESSION": "1"}, true, true},
+		{"not a terminal", map[string]string{"TERM_PROGRAM": "iTerm.app"}, false, false},
+		{"forced", map[string]string{"FORCE_HYPERLINK": "1"}, false, true},
+		{"forced off", map[string]string{"FORCE_HYPERLINK": "0", "TERM_PROGRAM": "iTerm.app"}, true, false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got := detectHyperlinks(func(key string) string {
+				return tc.env[key]
+			}, tc.terminal)
+			if got != tc.want {
+				t.Fatalf("want %v got %v", tc.want, got)
+			}
+		})
+	}
+}
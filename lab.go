@@ -0,0 +1,81 @@
+package color
+
+import "math"
+
+// lab is a color in the CIELAB space, where distances roughly match perceived differences. It uses the D65
+// white point of sRGB.
+type lab struct {
+	l, a, b float64
+}
+
+// D65 reference white in XYZ.
+const (
+	whiteX = 0.95047
+	whiteY = 1.0
+	whiteZ = 1.08883
+)
+
+// rgbToLab converts an sRGB color to CIELAB.
+func rgbToLab(r, g, b uint8) lab {
+	lr, lg, lb := srgbToLinear(r), srgbToLinear(g), srgbToLinear(b)
+	x := (0.4124564*lr + 0.3575761*lg + 0.1804375*lb) / whiteX
+	y := (0.2126729*lr + 0.7151522*lg + 0.0721750*lb) / whiteY
+	z := (0.0193339*lr + 0.1191920*lg + 0.9503041*lb) / whiteZ
+	fx, fy, fz := labF(x), labF(y), labF(z)
+	return lab{l: 116*fy - 16, a: 500 * (fx - fy), b: 200 * (fy - fz)}
+}
+
+// rgb converts c to the nearest sRGB color, clamping colors outside of the sRGB gamut.
+func (c lab) rgb() (r, g, b uint8) {
+	fy := (c.l + 16) / 116
+	fx := fy + c.a/500
+	fz := fy - c.b/200
+	x, y, z := labFInverse(fx)*whiteX, labFInverse(fy)*whiteY, labFInverse(fz)*whiteZ
+	lr := 3.2404542*x - 1.5371385*y - 0.4985314*z
+	lg := -0.9692660*x + 1.8760108*y + 0.0415560*z
+	lb := 0.0556434*x - 0.2040259*y + 1.0572252*z
+	return linearToSRGB(lr), linearToSRGB(lg), linearToSRGB(lb)
+}
+
+// mix returns the color at t between c and o, t = 0 is c and t = 1 is o.
+func (c lab) mix(o lab, t float64) lab {
+	return lab{l: c.l + (o.l-c.l)*t, a: c.a + (o.a-c.a)*t, b: c.b + (o.b-c.b)*t}
+}
+
+const (
+	labEpsilon = 216.0 / 24389
+	labKappa   = 24389.0 / 27
+)
+
+func labF(t float64) float64 {
+	if t > labEpsilon {
+		return math.Cbrt(t)
+	}
+	return (labKappa*t + 16) / 116
+}
+
+func labFInverse(t float64) float64 {
+	if t3 := t * t * t; t3 > labEpsilon {
+		return t3
+	}
+	return (116*t - 16) / labKappa
+}
+
+// srgbToLinear removes the gamma of an sRGB component, returning a value between 0 and 1.
+func srgbToLinear(v uint8) float64 {
+	c := float64(v) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB applies the sRGB gamma to a linear component, clamping it to 0..255.
+func linearToSRGB(c float64) uint8 {
+	if c <= 0.0031308 {
+		c *= 12.92
+	} else {
+		c = 1.055*math.Pow(c, 1/2.4) - 0.055
+	}
+	return uint8(math.Round(math.Max(0, math.Min(1, c)) * 255))
+}
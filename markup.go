@@ -0,0 +1,182 @@
+package color
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// defaultTheme resolves the roles used in markup passed to the package level functions.
+var defaultTheme = DefaultTheme()
+
+// Markup renders inline color tags in s, for example "<red>failed</red> in <bold>main.go</bold>" or
+// "[error]failed[/]". A tag holds a role of DefaultTheme or a style description as accepted by ParseStyle,
+// such as <bold red on black>. Angle and square brackets can be mixed. A closing tag, </...>, </>, [/...] or
+// [/], ends the innermost open tag and tags left open end with s. Tags nest, the inner style is merged onto
+// the outer one.
+//
+// Brackets which don't hold a known role or a valid style, such as "[1]" or "<none>", are kept as text. A
+// backslash in front of < or [ makes it literal, and \\ is a single backslash.
+//
+// Tags are removed and no escape sequences are written when colors are disabled.
+func Markup(s string) string {
+	return defaultTheme.Markup(s)
+}
+
+// Markupf renders the tags in format and then formats it with a. Tags in the arguments are not rendered, so
+// they can be filled with untrusted text.
+func Markupf(format string, a ...interface{}) string {
+	return defaultTheme.Markupf(format, a...)
+}
+
+// Markup renders the tags in s, resolving roles with t. See the package level Markup function.
+func (t Theme) Markup(s string) string {
+	return t.renderMarkup(Enabled(), ActiveProfile(), s)
+}
+
+// Markupf renders the tags in format, resolving roles with t, and then formats it with a.
+func (t Theme) Markupf(format string, a ...interface{}) string {
+	return fmt.Sprintf(t.Markup(format), a...)
+}
+
+// Markup renders the tags in s with the profile of the console, or removes them if the console doesn't write
+// colors. Roles are resolved with DefaultTheme.
+func (c *Console) Markup(s string) string {
+	on, p := c.policy()
+	return defaultTheme.renderMarkup(on, p, s)
+}
+
+// Markupf renders the tags in format for the console and then formats it with a.
+func (c *Console) Markupf(format string, a ...interface{}) string {
+	return fmt.Sprintf(c.Markup(format), a...)
+}
+
+// EscapeMarkup makes the brackets and backslashes in s literal, so that s can be inserted into markup.
+func EscapeMarkup(s string) string {
+	return markupEscaper.Replace(s)
+}
+
+var markupEscaper = strings.NewReplacer(`\`, `\\`, `<`, `\<`, `[`, `\[`)
+
+// FuncMap returns template functions which color text with the roles of DefaultTheme, see Theme.FuncMap.
+func FuncMap() template.FuncMap {
+	return defaultTheme.FuncMap()
+}
+
+// FuncMap returns functions for text/template. Each role of t whose name is a valid identifier becomes a
+// function, so {{error .Message}} prints the message in the error style. style applies a style description,
+// as in {{style "bold red" .Name}}, and escape makes a value safe to insert into markup rendered after the
+// template is executed. style and escape take precedence over roles of the same name, so a theme loaded from
+// user configuration can't replace them.
+func (t Theme) FuncMap() template.FuncMap {
+	funcs := template.FuncMap{}
+	for role := range t {
+		if !isIdentifier(role) {
+			continue
+		}
+		role := role
+		funcs[role] = func(a ...interface{}) string {
+			return t.Sprint(role, a...)
+		}
+	}
+	funcs["style"] = func(spec string, a ...interface{}) (string, error) {
+		s, err := ParseStyle(spec)
+		if err != nil {
+			return "", err
+		}
+		return s.Color().Sprint(a...), nil
+	}
+	funcs["escape"] = func(a ...interface{}) string {
+		return EscapeMarkup(fmt.Sprint(a...))
+	}
+	return funcs
+}
+
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		if r != '_' && !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && !(i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// renderMarkup renders the tags in s for a terminal with profile p, or removes them when on is false.
+func (t Theme) renderMarkup(on bool, p Profile, s string) string {
+	var b, text strings.Builder
+	stack := []Style{NewStyle()}
+	flush := func() {
+		if text.Len() == 0 {
+			return
+		}
+		if style := stack[len(stack)-1]; on && !style.IsZero() {
+			b.WriteString(style.Color().wrap(p, text.String()))
+		} else {
+			b.WriteString(text.String())
+		}
+		text.Reset()
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) && strings.IndexByte(`\<[`, s[i+1]) >= 0 {
+			text.WriteByte(s[i+1])
+			i++
+			continue
+		}
+		// a [ following ESC starts an escape sequence, which is passed through
+		if (c == '<' || c == '[') && (i == 0 || s[i-1] != esc) {
+			style, closing, n, ok := t.markupTag(s[i:])
+			if ok && !(closing && len(stack) == 1) {
+				flush()
+				if closing {
+					stack = stack[:len(stack)-1]
+				} else {
+					stack = append(stack, stack[len(stack)-1].Merge(style))
+				}
+				i += n - 1
+				continue
+			}
+		}
+		text.WriteByte(c)
+	}
+	flush()
+	return b.String()
+}
+
+// markupTag reads the tag at the start of s. It returns the style of an opening tag or whether it's a closing
+// tag, and the length of the tag. ok is false when s doesn't start with a tag.
+func (t Theme) markupTag(s string) (style Style, closing bool, n int, ok bool) {
+	closer := byte('>')
+	if s[0] == '[' {
+		closer = ']'
+	}
+	end := strings.IndexByte(s, closer)
+	if end < 0 {
+		return Style{}, false, 0, false
+	}
+	content := s[1:end]
+	if strings.ContainsAny(content, "<>[]\n") {
+		return Style{}, false, 0, false
+	}
+	if strings.HasPrefix(content, "/") {
+		return Style{}, true, end + 1, true
+	}
+	style, ok = t.tagStyle(strings.TrimSpace(content))
+	return style, false, end + 1, ok
+}
+
+// tagStyle returns the style named by the content of a tag: a role of t or a style description. Numbers
+// alone aren't taken as 256 palette colors so that text such as "[1]" stays as it is.
+func (t Theme) tagStyle(content string) (Style, bool) {
+	if content == "" {
+		return Style{}, false
+	}
+	if s, ok := t[content]; ok {
+		return s, true
+	}
+	if strings.Trim(content, "0123456789") == "" {
+		return Style{}, false
+	}
+	s, err := ParseStyle(content)
+	return s, err == nil
+}
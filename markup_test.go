@@ -0,0 +1,90 @@
+package color
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+)
+
+func TestMarkup(t *testing.T) {
+	t.Parallel()
+	theme := Theme{"error": NewStyle().Bold().Fg(FgRed)}
+	tt := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "hello", "hello"},
+		{"style", "<red>failed</red> in <bold>main.go</bold>", "\x1b[31mfailed\x1b[0m in \x1b[1mmain.go\x1b[0m"},
+		{"role", "[error]msg[/] done", "\x1b[1;31mmsg\x1b[0m done"},
+		{"description", "<bold blue on white>x</>", "\x1b[1;34;47mx\x1b[0m"},
+		{"nested", "<red>a<bold>b</bold>c</red>", "\x1b[31ma\x1b[0m\x1b[1;31mb\x1b[0m\x1b[31mc\x1b[0m"},
+		{"nested override", "[red]a[green]b[/]c[/]", "\x1b[31ma\x1b[0m\x1b[32mb\x1b[0m\x1b[31mc\x1b[0m"},
+		{"unclosed", "<red>a", "\x1b[31ma\x1b[0m"},
+		{"unknown tags kept", "<div>[1] [x]</div>", "<div>[1] [x]</div>"},
+		{"stray closing tag", "a</>b[/]", "a</>b[/]"},
+		{"escaped", `\<red>\[error] \\ \n`, `<red>[error] \ \n`},
+		{"unterminated", "<red", "<red"},
+		{"escape sequences kept", "[red]\x1b[4mx\x1b[24m[/]", "\x1b[31m\x1b[4mx\x1b[24m\x1b[0m"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assertEqualS(t, tc.want, theme.renderMarkup(true, TrueColor, tc.in))
+		})
+	}
+}
+
+func TestMarkupDisabled(t *testing.T) {
+	t.Parallel()
+	got := defaultTheme.renderMarkup(false, TrueColor, "<red>failed</> in [error]main.go[/] [1]")
+	assertEqualS(t, "failed in main.go [1]", got)
+}
+
+func TestMarkupProfile(t *testing.T) {
+	t.Parallel()
+	assertEqualS(t, "\x1b[38;5;208mx\x1b[0m", defaultTheme.renderMarkup(true, ANSI256, "<#ff8800>x</>"))
+}
+
+func TestConsoleMarkup(t *testing.T) {
+	t.Parallel()
+	cons := newMockConsole(&bytes.Buffer{})
+	assertEqualS(t, "50% failed", cons.Markupf("<error>%d%% failed</>", 50))
+	cons.SetMode(Always)
+	assertEqualS(t, "\x1b[1;31m<b>\x1b[0m", cons.Markupf("<error>%s</>", "<b>"))
+}
+
+func TestEscapeMarkup(t *testing.T) {
+	t.Parallel()
+	in := `<red>[a]\b`
+	assertEqualS(t, `\<red>\[a]\\b`, EscapeMarkup(in))
+	assertEqualS(t, in, defaultTheme.renderMarkup(true, TrueColor, EscapeMarkup(in)))
+}
+
+// can't be parallel since it enables colors globally
+func TestMarkupTemplate(t *testing.T) {
+	SetMode(Always)
+	defer ReloadEnv()
+	tmpl := template.Must(template.New("msg").Funcs(FuncMap()).Parse(
+		`{{error "failed"}} in {{style "bold" .File}}, <muted>{{escape .Note}}</muted>`))
+	var b bytes.Buffer
+	err := tmpl.Execute(&b, map[string]string{"File": "main.go", "Note": "[x]"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "\x1b[1;31mfailed\x1b[0m in \x1b[1mmain.go\x1b[0m, \x1b[90m[x]\x1b[0m"
+	assertEqualS(t, want, Markup(b.String()))
+
+	tmpl = template.Must(template.New("bad").Funcs(FuncMap()).Parse(`{{style "orange" "x"}}`))
+	if err := tmpl.Execute(&b, nil); err == nil {
+		t.Fatal("expected an error for an unknown style")
+	}
+
+	theme := Theme{"style": NewStyle().Fg(FgRed), "escape": NewStyle().Bold()}
+	tmpl = template.Must(template.New("builtins").Funcs(theme.FuncMap()).Parse(
+		`{{style "bold" "a"}}{{escape "<"}}`))
+	b.Reset()
+	if err := tmpl.Execute(&b, nil); err != nil {
+		t.Fatal(err)
+	}
+	assertEqualS(t, "\x1b[1ma\x1b[0m\\<", b.String())
+}
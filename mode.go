@@ -0,0 +1,74 @@
+package color
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ColorMode controls when colors are written. It can be set globally with SetMode and for a single console
+// with Console.SetMode. ColorMode implements flag.Value so it can back a --color=auto|always|never flag.
+type ColorMode int
+
+const (
+	// Auto writes colors when the output is a terminal. A console in Auto mode follows the global mode.
+	Auto ColorMode = iota
+	// Always writes colors, even when the output isn't a terminal.
+	Always
+	// Never strips colors from the output.
+	Never
+)
+
+// String returns the name of the mode as used by --color flags.
+func (m ColorMode) String() string {
+	switch m {
+	case Auto:
+		return "auto"
+	case Always:
+		return "always"
+	case Never:
+		return "never"
+	}
+	return "unknown mode"
+}
+
+// Set parses s with ParseColorMode and stores the result in m.
+func (m *ColorMode) Set(s string) error {
+	v, err := ParseColorMode(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// ParseColorMode returns the mode named by s, one of auto, always or never.
+func ParseColorMode(s string) (ColorMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "auto":
+		return Auto, nil
+	case "always":
+		return Always, nil
+	case "never":
+		return Never, nil
+	}
+	return Auto, fmt.Errorf("invalid color mode %q, expected auto, always or never", s)
+}
+
+var globalMode ColorMode
+var globalModeLock sync.RWMutex
+
+// SetMode sets when colors are written globally. Consoles in Auto mode, including Stdout and Stderr, follow
+// the global mode. The initial mode is read from the environment, see ReloadEnv.
+func SetMode(m ColorMode) {
+	globalModeLock.Lock()
+	defer globalModeLock.Unlock()
+	globalMode = m
+}
+
+// Mode returns the global color mode.
+func Mode() ColorMode {
+	globalModeLock.RLock()
+	defer globalModeLock.RUnlock()
+	return globalMode
+}
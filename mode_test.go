@@ -0,0 +1,118 @@
+package color
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"testing"
+)
+
+func TestParseColorMode(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		in   string
+		want ColorMode
+	}{
+		{"auto", Auto},
+		{"always", Always},
+		{"never", Never},
+		{" Always ", Always},
+	}
+	for _, tc := range tt {
+		got, err := ParseColorMode(tc.in)
+		if err != nil {
+			t.Fatalf("no error expected for %q: %s", tc.in, err)
+		}
+		if got != tc.want {
+			t.Fatalf("want %s got %s", tc.want, got)
+		}
+		if back, _ := ParseColorMode(got.String()); back != got {
+			t.Fatalf("%s doesn't round trip", got)
+		}
+	}
+	if _, err := ParseColorMode("sometimes"); err == nil {
+		t.Fatal("expected an error for an invalid mode")
+	}
+}
+
+func TestColorModeFlag(t *testing.T) {
+	t.Parallel()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	var mode ColorMode
+	fs.Var(&mode, "color", "when to use colors")
+	if err := fs.Parse([]string{"--color=never"}); err != nil {
+		t.Fatal("no error expected", err)
+	}
+	if mode != Never {
+		t.Fatalf("want %s got %s", Never, mode)
+	}
+	if err := fs.Parse([]string{"--color=blue"}); err == nil {
+		t.Fatal("expected an error for an invalid mode")
+	}
+}
+
+func TestGlobalMode(t *testing.T) {
+	// can't be parallel since we're changing the global mode
+	defer ReloadEnv()
+	col := New(FgRed)
+
+	SetMode(Always)
+	if Mode() != Always || !Enabled() {
+		t.Fatalf("want %s got %s", Always, Mode())
+	}
+	var buff bytes.Buffer
+	cons := NewConsoleWriter(&buff)
+	_, _ = cons.Print(col, "a")
+	cons.SetMode(Never)
+	_, _ = cons.Print(col, "b")
+
+	SetMode(Never)
+	if Mode() != Never || Enabled() {
+		t.Fatalf("want %s got %s", Never, Mode())
+	}
+	assertEqualS(t, "c", col.Sprint("c"))
+	cons = NewConsoleWriter(&buff)
+	_, _ = cons.Print(col, "d")
+	cons.SetMode(Always)
+	_, _ = cons.Print(col, "e")
+	assertEqualS(t, "\x1b[31ma\x1b[0mbd\x1b[31me\x1b[0m", buff.String())
+}
+
+func TestGlobalModeStdout(t *testing.T) {
+	// can't be parallel since we're changing the global mode
+	defer ReloadEnv()
+	cons := Stdout()
+	SetMode(Always)
+	cons.Lock()
+	colored := cons.writer() == cons.colored
+	cons.Unlock()
+	if !colored {
+		t.Fatal("expected stdout to follow the global mode")
+	}
+	SetMode(Never)
+	cons.Lock()
+	colored = cons.writer() == cons.colored
+	cons.Unlock()
+	if colored {
+		t.Fatal("expected stdout to follow the global mode")
+	}
+}
+
+func TestGlobalModeConsoleWriter(t *testing.T) {
+	// can't be parallel since we're changing the global mode
+	defer ReloadEnv()
+	SetMode(Auto)
+	var buff bytes.Buffer
+	cons := NewConsoleWriter(&buff, WithProfile(TrueColor))
+	col := New(FgRed)
+	SetMode(Always)
+	_, _ = cons.Print(col, "a")
+	assertEqualS(t, "\x1b[31ma\x1b[0m", buff.String())
+	assertEqualS(t, buff.String(), cons.Sprint(col, "a"))
+	buff.Reset()
+	SetMode(Never)
+	_, _ = cons.Print(col, "b")
+	assertEqualS(t, "b", buff.String())
+	assertEqualS(t, "b", cons.Sprint(col, "b"))
+}
@@ -0,0 +1,259 @@
+package color
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// attributesByName maps lower case attribute names to attributes, it's the inverse of attributeNames.
+var attributesByName = func() map[string]Attribute {
+	m := make(map[string]Attribute, len(attributeNames))
+	for a, name := range attributeNames {
+		m[strings.ToLower(name)] = a
+	}
+	return m
+}()
+
+var paletteConstructors = map[string]func(uint8) Attribute{
+	"fg256": Fg256,
+	"bg256": Bg256,
+	"ul256": Ul256,
+}
+
+var rgbConstructors = map[string]func(r, g, b uint8) Attribute{
+	"fgrgb": FgRGB,
+	"bgrgb": BgRGB,
+	"ulrgb": UlRGB,
+}
+
+// ParseAttribute returns the Attribute called s. It accepts the names returned by Attribute.Name, such as
+// "FgHiCyan", "Fg256(208)" or "BgRGB(0,0,128)", and hex colors such as "#ff8800" which set the foreground.
+// Case and spaces are ignored.
+func ParseAttribute(s string) (Attribute, error) {
+	name := strings.ToLower(strings.Join(strings.Fields(s), ""))
+	if a, ok := attributesByName[name]; ok {
+		return a, nil
+	}
+	if strings.HasPrefix(name, "#") {
+		r, g, b, ok := parseHex(name)
+		if !ok {
+			return 0, fmt.Errorf("invalid hex color %q, expected #rrggbb or #rgb", s)
+		}
+		return FgRGB(r, g, b), nil
+	}
+	if fn, args, ok := splitCall(name); ok {
+		if f, ok := paletteConstructors[fn]; ok {
+			n, err := colorArgs(s, args, 1)
+			if err != nil {
+				return 0, err
+			}
+			return f(n[0]), nil
+		}
+		if f, ok := rgbConstructors[fn]; ok {
+			n, err := colorArgs(s, args, 3)
+			if err != nil {
+				return 0, err
+			}
+			return f(n[0], n[1], n[2]), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown attribute %q", s)
+}
+
+// splitCall splits a name such as "fg256(208)" into the function and its comma separated arguments.
+func splitCall(name string) (fn string, args []string, ok bool) {
+	open := strings.IndexByte(name, '(')
+	if open < 0 || !strings.HasSuffix(name, ")") {
+		return "", nil, false
+	}
+	return name[:open], strings.Split(name[open+1:len(name)-1], ","), true
+}
+
+// colorArgs converts the arguments of attribute s to color components.
+func colorArgs(s string, args []string, want int) ([]uint8, error) {
+	if len(args) != want {
+		return nil, fmt.Errorf("invalid attribute %q, expected %d arguments got %d", s, want, len(args))
+	}
+	n := make([]uint8, want)
+	for i, arg := range args {
+		v, ok := uint8Param(arg)
+		if !ok {
+			return nil, fmt.Errorf("invalid attribute %q, %q is not a number between 0 and 255", s, arg)
+		}
+		n[i] = v
+	}
+	return n, nil
+}
+
+// styleWords maps the decorations accepted by ParseStyle to attributes.
+var styleWords = map[string]Attribute{
+	"bold":            Bold,
+	"faint":           Faint,
+	"dim":             Faint,
+	"italic":          Italic,
+	"underline":       Underline,
+	"doubleunderline": DoubleUnderline,
+	"curlyunderline":  CurlyUnderline,
+	"dottedunderline": DottedUnderline,
+	"overline":        Overline,
+	"blink":           BlinkSlow,
+	"rapidblink":      BlinkRapid,
+	"reverse":         ReverseVideo,
+	"conceal":         Concealed,
+	"hidden":          Concealed,
+	"crossedout":      CrossedOut,
+	"strikethrough":   CrossedOut,
+}
+
+// ParseStyle parses a description of a style such as "bold red on black". The description is a list of
+// words separated by spaces or commas:
+//
+//   - decorations: bold, faint (or dim), italic, underline, double-underline, curly-underline,
+//     dotted-underline, overline, blink, rapid-blink, reverse, conceal (or hidden), crossed-out (or
+//     strikethrough)
+//   - colors: black, red, green, yellow, blue, magenta, cyan, white, with a bright- or hi- prefix for the
+//     bright variants, gray for bright black, a number from 0 to 255 for the 256 color palette, #rrggbb or
+//     rgb(r,g,b) for 24 bit colors
+//   - on followed by a color sets the background
+//   - any name accepted by ParseAttribute, such as FgHiCyan or Ul256(208)
+//
+// Case is ignored. The output of Style.String is accepted as well, so styles round-trip. An error describing
+// the first word that isn't understood is returned.
+func ParseStyle(s string) (Style, error) {
+	words := splitStyle(s)
+	style := NewStyle()
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if word == "on" {
+			if i+1 == len(words) {
+				return Style{}, fmt.Errorf("missing color after %q in style %q", word, s)
+			}
+			i++
+			a, ok := parseColorWord(words[i])
+			if !ok {
+				a, ok = parseColorAttribute(words[i])
+			}
+			if !ok {
+				return Style{}, fmt.Errorf("unknown background color %q in style %q", words[i], s)
+			}
+			style = style.Bg(a)
+			continue
+		}
+		if a, ok := styleWords[strings.NewReplacer("-", "", "_", "").Replace(word)]; ok {
+			style = style.With(a)
+			continue
+		}
+		if a, ok := parseColorWord(word); ok {
+			style = style.Fg(a)
+			continue
+		}
+		a, err := ParseAttribute(word)
+		if err != nil {
+			return Style{}, fmt.Errorf("invalid style %q: %w", s, err)
+		}
+		style = style.With(a)
+	}
+	return style, nil
+}
+
+// splitStyle splits a style description into lower case words. Commas and spaces inside parentheses don't
+// split words, and a surrounding "Style(...)" as written by Style.String is removed.
+func splitStyle(s string) []string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if strings.HasPrefix(s, "style(") && strings.HasSuffix(s, ")") {
+		s = s[len("style(") : len(s)-1]
+	}
+	var words []string
+	var word strings.Builder
+	depth := 0
+	for _, r := range s {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case depth == 0 && (r == ',' || unicode.IsSpace(r)):
+			if word.Len() > 0 {
+				words = append(words, word.String())
+				word.Reset()
+			}
+			continue
+		case depth > 0 && unicode.IsSpace(r):
+			continue
+		}
+		word.WriteRune(r)
+	}
+	if word.Len() > 0 {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+// parseColorWord converts a color as written in a style description to a foreground color.
+func parseColorWord(word string) (Attribute, bool) {
+	if n, ok := uint8Param(word); ok {
+		return Fg256(n), true
+	}
+	if strings.HasPrefix(word, "#") {
+		r, g, b, ok := parseHex(word)
+		return FgRGB(r, g, b), ok
+	}
+	if fn, args, ok := splitCall(word); ok && fn == "rgb" && len(args) == 3 {
+		r, rok := uint8Param(args[0])
+		g, gok := uint8Param(args[1])
+		b, bok := uint8Param(args[2])
+		return FgRGB(r, g, b), rok && gok && bok
+	}
+	name := strings.NewReplacer("-", "", "_", "").Replace(word)
+	if name == "gray" || name == "grey" {
+		return FgHiBlack, true
+	}
+	offset := 0
+	for _, prefix := range []string{"bright", "hi"} {
+		if strings.HasPrefix(name, prefix) {
+			name = name[len(prefix):]
+			offset = 8
+			break
+		}
+	}
+	for i, c := range colorNames {
+		if c == name {
+			return ansiForeground[i+offset], true
+		}
+	}
+	return 0, false
+}
+
+// parseColorAttribute parses a foreground or background color written as an attribute name, for example
+// FgRed or Bg256(17).
+func parseColorAttribute(word string) (Attribute, bool) {
+	a, err := ParseAttribute(word)
+	return a, err == nil && (isForeground(a) || isBackground(a))
+}
+
+// MarshalText returns the names of the attributes of s separated by spaces, which ParseStyle accepts.
+func (s Style) MarshalText() ([]byte, error) {
+	attrs := s.Attributes()
+	names := make([]string, len(attrs))
+	for i, a := range attrs {
+		names[i] = a.Name()
+	}
+	return []byte(strings.Join(names, " ")), nil
+}
+
+// UnmarshalText sets s to the style described by text, see ParseStyle. It lets styles be read from
+// configuration files.
+func (s *Style) UnmarshalText(text []byte) error {
+	style, err := ParseStyle(string(text))
+	if err != nil {
+		return err
+	}
+	*s = style
+	return nil
+}
+
+// Set parses v with ParseStyle, implementing flag.Value.
+func (s *Style) Set(v string) error {
+	return s.UnmarshalText([]byte(v))
+}
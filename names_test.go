@@ -0,0 +1,175 @@
+package color
+
+import (
+	"encoding/json"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestParseAttributeRoundTrip(t *testing.T) {
+	t.Parallel()
+	attrs := []Attribute{Fg256(0), Bg256(255), Ul256(208), FgRGB(1, 2, 3), BgRGB(255, 136, 0), UlRGB(0, 0, 0)}
+	for a := range attributeNames {
+		attrs = append(attrs, a)
+	}
+	for _, a := range attrs {
+		got, err := ParseAttribute(a.Name())
+		if err != nil {
+			t.Fatalf("%s: %v", a.Name(), err)
+		}
+		if got != a {
+			t.Fatalf("want %s got %s", a.Name(), got.Name())
+		}
+	}
+}
+
+func TestParseAttribute(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		in   string
+		want Attribute
+	}{
+		{"fghicyan", FgHiCyan},
+		{" FgRGB(1, 2, 3) ", FgRGB(1, 2, 3)},
+		{"#ff8800", FgRGB(255, 136, 0)},
+		{"#f80", FgRGB(255, 136, 0)},
+	}
+	for _, tc := range tt {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := ParseAttribute(tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Fatalf("want %s got %s", tc.want.Name(), got.Name())
+			}
+		})
+	}
+}
+
+func TestParseAttributeErrors(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		in   string
+		want string
+	}{
+		{"FgOrange", `unknown attribute "FgOrange"`},
+		{"#ff88", `invalid hex color "#ff88", expected #rrggbb or #rgb`},
+		{"Fg256(300)", `invalid attribute "Fg256(300)", "300" is not a number between 0 and 255`},
+		{"BgRGB(1,2)", `invalid attribute "BgRGB(1,2)", expected 3 arguments got 2`},
+		{"", `unknown attribute ""`},
+	}
+	for _, tc := range tt {
+		t.Run(tc.in, func(t *testing.T) {
+			_, err := ParseAttribute(tc.in)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			assertEqualS(t, tc.want, err.Error())
+		})
+	}
+}
+
+func TestParseStyle(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		in   string
+		want Style
+	}{
+		{"bold red on black", NewStyle().Bold().Fg(FgRed).Bg(BgBlack)},
+		{"Italic, bright-blue on hi-white", NewStyle().Italic().Fg(FgHiBlue).Bg(BgHiWhite)},
+		{"dim gray", NewStyle().Faint().Fg(FgHiBlack)},
+		{"208 on 236", NewStyle().Fg(Fg256(208)).Bg(Bg256(236))},
+		{"#ff8800 on rgb(0, 0, 128)", NewStyle().Fg(FgRGB(255, 136, 0)).Bg(BgRGB(0, 0, 128))},
+		{"curly-underline Ul256(196)", NewStyle().CurlyUnderline().Ul(Ul256(196))},
+		{"FgHiCyan on BgRed", NewStyle().Fg(FgHiCyan).Bg(BgRed)},
+		{"strikethrough NormalIntensity", NewStyle().CrossedOut().With(NormalIntensity)},
+		{"", NewStyle()},
+	}
+	for _, tc := range tt {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := ParseStyle(tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Fatalf("want %s got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestParseStyleRoundTrip(t *testing.T) {
+	t.Parallel()
+	styles := []Style{
+		NewStyle().Bold().Underline().Fg(FgWhite).Bg(Bg256(17)),
+		NewStyle().DottedUnderline().Ul(UlRGB(1, 2, 3)).Fg(FgRGB(4, 5, 6)),
+		NewStyle().With(DefaultFg, NotItalic),
+		NewStyle(),
+	}
+	for _, s := range styles {
+		got, err := ParseStyle(s.String())
+		if err != nil {
+			t.Fatalf("%s: %v", s, err)
+		}
+		if got != s {
+			t.Fatalf("want %s got %s", s, got)
+		}
+	}
+}
+
+func TestParseStyleErrors(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		in   string
+		want string
+	}{
+		{"bold orange", `invalid style "bold orange": unknown attribute "orange"`},
+		{"red on", `missing color after "on" in style "red on"`},
+		{"red on bold", `unknown background color "bold" in style "red on bold"`},
+		{"#12345", `invalid style "#12345": invalid hex color "#12345", expected #rrggbb or #rgb`},
+	}
+	for _, tc := range tt {
+		t.Run(tc.in, func(t *testing.T) {
+			_, err := ParseStyle(tc.in)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			assertEqualS(t, tc.want, err.Error())
+		})
+	}
+}
+
+func TestStyleText(t *testing.T) {
+	t.Parallel()
+	var cfg struct {
+		Error Style `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(`{"error": "bold red on black"}`), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	want := NewStyle().Bold().Fg(FgRed).Bg(BgBlack)
+	if cfg.Error != want {
+		t.Fatalf("want %s got %s", want, cfg.Error)
+	}
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqualS(t, `{"error":"Bold FgRed BgBlack"}`, string(b))
+	err = json.Unmarshal([]byte(`{"error": "purple"}`), &cfg)
+	if err == nil || !strings.Contains(err.Error(), "purple") {
+		t.Fatalf("expected an error naming the unknown color got %v", err)
+	}
+
+	var s Style
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&s, "style", "")
+	if err := fs.Parse([]string{"-style", "italic green"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := NewStyle().Italic().Fg(FgGreen); s != want {
+		t.Fatalf("want %s got %s", want, s)
+	}
+}
@@ -0,0 +1,262 @@
+package color
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Span is a run of text displayed with a single style.
+type Span struct {
+	Text  string
+	Style Style
+}
+
+// Parse splits s into spans of text with the style set by the SGR codes preceding them. Codes accumulate
+// until they are reset, so "\x1b[1m\x1b[31mA\x1b[22mB" yields bold red A followed by red B. Other escape
+// sequences, such as cursor movement, are dropped.
+func Parse(s string) []Span {
+	var p Parser
+	_, _ = p.Write([]byte(s))
+	return p.Spans()
+}
+
+// ParseReader parses everything read from r, see Parse.
+func ParseReader(r io.Reader) ([]Span, error) {
+	var p Parser
+	if _, err := io.Copy(&p, r); err != nil {
+		return nil, err
+	}
+	return p.Spans(), nil
+}
+
+// Parser turns a stream of colored text into spans. Text is passed to it with Write, escape sequences may be
+// split across writes. The zero value is ready to use.
+type Parser struct {
+	st     stripper
+	style  Style
+	params []byte
+	text   []byte
+	spans  []Span
+}
+
+// Write parses p. It never returns an error.
+func (p *Parser) Write(b []byte) (int, error) {
+	for _, c := range b {
+		prev := p.st.state
+		if p.st.step(c) {
+			p.text = append(p.text, c)
+			continue
+		}
+		switch {
+		case prev == stateCSI && p.st.state == stateText:
+			if c == 'm' && !isPrivateCSI(p.params) {
+				p.flush()
+				p.style = applySGR(p.style, string(p.params))
+			}
+		case p.st.state == stateCSI && prev != stateCSI:
+			p.params = p.params[:0]
+		case p.st.state == stateCSI:
+			p.params = append(p.params, c)
+		}
+	}
+	return len(b), nil
+}
+
+// Spans returns the spans parsed so far. Adjacent text with the same style is joined into one span.
+func (p *Parser) Spans() []Span {
+	p.flush()
+	spans := make([]Span, len(p.spans))
+	copy(spans, p.spans)
+	return spans
+}
+
+// flush turns pending text into a span.
+func (p *Parser) flush() {
+	if len(p.text) == 0 {
+		return
+	}
+	if n := len(p.spans); n > 0 && p.spans[n-1].Style == p.style {
+		p.spans[n-1].Text += string(p.text)
+	} else {
+		p.spans = append(p.spans, Span{Text: string(p.text), Style: p.style})
+	}
+	p.text = p.text[:0]
+}
+
+// isPrivateCSI reports whether the parameters of a CSI sequence start with a private marker, one of < = > ?.
+// Such sequences, for example the xterm modifyOtherKeys setting "\x1b[>4;2m", aren't SGR even when they end
+// with m.
+func isPrivateCSI(params []byte) bool {
+	return len(params) > 0 && params[0] >= 0x3c && params[0] <= 0x3f
+}
+
+// applySGR returns s changed by the parameters of an SGR sequence, for example "1;38;5;208". An empty
+// parameter means 0, so "\x1b[m" and "\x1b[;1m" reset the style.
+func applySGR(s Style, params string) Style {
+	fields := strings.Split(params, delimiter)
+	for i := 0; i < len(fields); i++ {
+		if strings.Contains(fields[i], ":") {
+			s = applySubparams(s, strings.Split(fields[i], ":"))
+			continue
+		}
+		if fields[i] == "" {
+			s = Style{}
+			continue
+		}
+		code, err := strconv.Atoi(fields[i])
+		if err != nil {
+			continue
+		}
+		switch code {
+		case 38, 48, 58:
+			a, n := extendedColor(fields[i+1:])
+			i += n
+			s = applyExtendedColor(s, code, a)
+		default:
+			s = applyCode(s, code)
+		}
+	}
+	return s
+}
+
+// applySubparams handles codes which use colons to separate their parameters, as in 38:2::255:136:0 or 4:3.
+func applySubparams(s Style, sub []string) Style {
+	code, err := strconv.Atoi(sub[0])
+	if err != nil {
+		return s
+	}
+	switch code {
+	case 38, 48, 58:
+		args := sub[1:]
+		// 38:2:colorspace:r:g:b carries an optional color space id
+		if len(args) == 5 && args[0] == "2" {
+			args = append([]string{"2"}, args[2:]...)
+		}
+		a, _ := extendedColor(args)
+		return applyExtendedColor(s, code, a)
+	case 4:
+		if len(sub) < 2 {
+			return s.Underline()
+		}
+		switch sub[1] {
+		case "0":
+			return s.without(underlineStyles...)
+		case "2":
+			return s.DoubleUnderline()
+		case "3":
+			return s.CurlyUnderline()
+		case "4", "5":
+			return s.DottedUnderline()
+		}
+		return s.Underline()
+	}
+	return applyCode(s, code)
+}
+
+// applyExtendedColor sets color a as the foreground, background or underline color depending on whether
+// code is 38, 48 or 58.
+func applyExtendedColor(s Style, code int, a Attribute) Style {
+	if a == 0 {
+		return s
+	}
+	switch code {
+	case 38:
+		return s.Fg(a)
+	case 48:
+		return s.Bg(a)
+	}
+	return s.Ul(a)
+}
+
+// extendedColor reads the parameters following 38 or 48. It returns the foreground color they describe and
+// the number of parameters used.
+func extendedColor(args []string) (Attribute, int) {
+	if len(args) == 0 {
+		return 0, 0
+	}
+	switch args[0] {
+	case "5":
+		if len(args) < 2 {
+			return 0, len(args)
+		}
+		n, ok := uint8Param(args[1])
+		if !ok {
+			return 0, 2
+		}
+		return Fg256(n), 2
+	case "2":
+		if len(args) < 4 {
+			return 0, len(args)
+		}
+		r, rok := uint8Param(args[1])
+		g, gok := uint8Param(args[2])
+		b, bok := uint8Param(args[3])
+		if !rok || !gok || !bok {
+			return 0, 4
+		}
+		return FgRGB(r, g, b), 4
+	}
+	return 0, 1
+}
+
+func uint8Param(s string) (uint8, bool) {
+	n, err := strconv.ParseUint(s, 10, 8)
+	return uint8(n), err == nil
+}
+
+// applyCode returns s changed by a single SGR code.
+func applyCode(s Style, code int) Style {
+	switch {
+	case code == 0:
+		return Style{}
+	case code >= 1 && code <= 9:
+		return s.With(fixedAttribute(code))
+	case code == 21:
+		return s.DoubleUnderline()
+	case code == 53:
+		return s.Overline()
+	case code == 55:
+		return s.without(Overline)
+	case code == 59:
+		s.ul = 0
+	case code == 22:
+		return s.without(Bold, Faint)
+	case code == 23:
+		return s.without(Italic)
+	case code == 24:
+		return s.without(underlineStyles...)
+	case code == 25:
+		return s.without(BlinkSlow, BlinkRapid)
+	case code == 27:
+		return s.without(ReverseVideo)
+	case code == 28:
+		return s.without(Concealed)
+	case code == 29:
+		return s.without(CrossedOut)
+	case code >= 30 && code <= 37:
+		return s.Fg(ansiForeground[code-30])
+	case code == 39:
+		s.fg = 0
+	case code >= 40 && code <= 47:
+		return s.Bg(ansiBackground[code-40])
+	case code == 49:
+		s.bg = 0
+	case code >= 90 && code <= 97:
+		return s.Fg(ansiForeground[code-90+8])
+	case code >= 100 && code <= 107:
+		return s.Bg(ansiBackground[code-100+8])
+	}
+	return s
+}
+
+// fixedAttribute returns the Attribute for an SGR code of the fixed set, or 0 when there is none.
+func fixedAttribute(code int) Attribute {
+	c := strconv.Itoa(code)
+	for a, sgr := range attributeToSGRCode {
+		if sgr == c {
+			return a
+		}
+	}
+	return 0
+}
@@ -0,0 +1,100 @@
+package color
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	red := NewStyle().Fg(FgRed)
+	tt := []struct {
+		name string
+		in   string
+		want []Span
+	}{
+		{"plain", "hello", []Span{{"hello", NewStyle()}}},
+		{"empty", "", []Span{}},
+		{"colored", "\x1b[31mred\x1b[0m plain", []Span{{"red", red}, {" plain", NewStyle()}}},
+		{"cumulative", "\x1b[1m\x1b[31mA\x1b[22mB", []Span{{"A", red.Bold()}, {"B", red}}},
+		{"chained", "\x1b[1;4;37;40mx", []Span{{"x", NewStyle().Bold().Underline().Fg(FgWhite).Bg(BgBlack)}}},
+		{"empty reset", "\x1b[31ma\x1b[mb", []Span{{"a", red}, {"b", NewStyle()}}},
+		{"empty parameters reset", "\x1b[31m\x1b[;1mA\x1b[3;;4mB", []Span{
+			{"A", NewStyle().Bold()}, {"B", NewStyle().Underline()},
+		}},
+		{"default colors", "\x1b[31;42ma\x1b[39mb\x1b[49mc", []Span{
+			{"a", red.Bg(BgGreen)}, {"b", NewStyle().Bg(BgGreen)}, {"c", NewStyle()},
+		}},
+		{"bright", "\x1b[91;104mx", []Span{{"x", NewStyle().Fg(FgHiRed).Bg(BgHiBlue)}}},
+		{"256", "\x1b[38;5;208;48;5;17mx", []Span{{"x", NewStyle().Fg(Fg256(208)).Bg(Bg256(17))}}},
+		{"rgb", "\x1b[1;38;2;255;136;0mx", []Span{{"x", NewStyle().Bold().Fg(FgRGB(255, 136, 0))}}},
+		{"rgb colons", "\x1b[38:2::1:2:3mx\x1b[48:5:9my", []Span{
+			{"x", NewStyle().Fg(FgRGB(1, 2, 3))}, {"y", NewStyle().Fg(FgRGB(1, 2, 3)).Bg(Bg256(9))},
+		}},
+		{"curly underline", "\x1b[4:3mx\x1b[4:0my", []Span{{"x", NewStyle().CurlyUnderline()}, {"y", NewStyle()}}},
+		{"underline styles", "\x1b[21ma\x1b[4:4mb\x1b[4mc\x1b[24md", []Span{
+			{"a", NewStyle().DoubleUnderline()}, {"b", NewStyle().DottedUnderline()},
+			{"c", NewStyle().Underline()}, {"d", NewStyle()},
+		}},
+		{"underline color", "\x1b[4;58;5;208ma\x1b[58:2::1:2:3mb\x1b[59mc", []Span{
+			{"a", NewStyle().Underline().Ul(Ul256(208))}, {"b", NewStyle().Underline().Ul(UlRGB(1, 2, 3))},
+			{"c", NewStyle().Underline()},
+		}},
+		{"overline", "\x1b[53ma\x1b[55mb", []Span{{"a", NewStyle().Overline()}, {"b", NewStyle()}}},
+		{"off codes", "\x1b[1;3;4;5;7;8;9mx\x1b[23;24;25;27;28;29my", []Span{
+			{"x", NewStyle().With(Bold, Italic, Underline, BlinkSlow, ReverseVideo, Concealed, CrossedOut)},
+			{"y", NewStyle().Bold()},
+		}},
+		{"same style joined", "\x1b[31ma\x1b[31mb", []Span{{"ab", red}}},
+		{"other sequences dropped", "\x1b[2K\x1b[31ma\x1b]0;title\x07b", []Span{{"ab", red}}},
+		{"private sequences dropped", "\x1b[>4;2ma\x1b[?1;2mb", []Span{{"ab", NewStyle()}}},
+		{"invalid parameters", "\x1b[38;5;300;9999mx", []Span{{"x", NewStyle()}}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Parse(tc.in)
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Fatalf("want %v got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestParseColorOutput(t *testing.T) {
+	t.Parallel()
+	col := New(Bold, FgGreen, Bg256(236))
+	s := col.wrap(TrueColor, "ok") + " done"
+	want := []Span{
+		{"ok", NewStyle().Bold().Fg(FgGreen).Bg(Bg256(236))},
+		{" done", NewStyle()},
+	}
+	if got := Parse(s); !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v got %v", want, got)
+	}
+}
+
+func TestParser(t *testing.T) {
+	t.Parallel()
+	in := "\x1b[1;31mbold red\x1b[0m\x1b[38;2;1;2;3m rgb\x1b[0m"
+	want := []Span{
+		{"bold red", NewStyle().Bold().Fg(FgRed)},
+		{" rgb", NewStyle().Fg(FgRGB(1, 2, 3))},
+	}
+	// feed the parser one byte at a time so sequences are split across writes
+	var p Parser
+	for i := 0; i < len(in); i++ {
+		_, _ = p.Write([]byte{in[i]})
+	}
+	if got := p.Spans(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v got %v", want, got)
+	}
+
+	got, err := ParseReader(strings.NewReader(in))
+	if err != nil {
+		t.Fatal("no error expected", err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v got %v", want, got)
+	}
+}
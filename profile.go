@@ -0,0 +1,255 @@
+package color
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// Profile describes the range of colors a terminal is able to display. Colors outside of a Console's
+// profile are converted to the nearest color the terminal supports.
+type Profile int
+
+const (
+	// TrueColor terminals display 24 bit colors. It is the zero value, colors are written unchanged.
+	TrueColor Profile = iota
+	// ANSI256 terminals display the 256 color palette.
+	ANSI256
+	// ANSI terminals only display the 16 basic colors.
+	ANSI
+	numProfiles
+)
+
+// String returns the name of the profile.
+func (p Profile) String() string {
+	switch p {
+	case TrueColor:
+		return "TrueColor"
+	case ANSI256:
+		return "ANSI256"
+	case ANSI:
+		return "ANSI"
+	}
+	return "unknown profile"
+}
+
+// DetectProfile inspects COLORTERM and TERM to determine which colors the terminal supports. ANSI is
+// returned when nothing more capable is advertised. A level set with FORCE_COLOR is treated as a minimum.
+func DetectProfile() Profile {
+	return detectProfile(os.Getenv)
+}
+
+func detectProfile(getenv func(string) string) Profile {
+	p := detectTerminalProfile(getenv)
+	if forced, ok := forceColorLevel(getenv("FORCE_COLOR")); ok && forced < p {
+		return forced
+	}
+	return p
+}
+
+func detectTerminalProfile(getenv func(string) string) Profile {
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return TrueColor
+	}
+	term := strings.ToLower(getenv("TERM"))
+	switch {
+	case strings.HasSuffix(term, "-direct"),
+		strings.HasSuffix(term, "-truecolor"),
+		term == "xterm-kitty",
+		term == "xterm-ghostty",
+		term == "alacritty",
+		term == "wezterm":
+		return TrueColor
+	case getenv("WT_SESSION") != "":
+		// Windows Terminal doesn't set TERM but supports 24 bit colors.
+		return TrueColor
+	case getenv("TERM_PROGRAM") == "iTerm.app":
+		return TrueColor
+	case strings.Contains(term, "256color"),
+		getenv("TERM_PROGRAM") == "Apple_Terminal":
+		return ANSI256
+	}
+	return ANSI
+}
+
+var activeProfile Profile
+var profileLock sync.RWMutex
+
+// SetProfile sets the profile used by Color.Sprint functions and by consoles created afterwards. It is
+// detected from the environment when the package is loaded.
+func SetProfile(p Profile) {
+	profileLock.Lock()
+	defer profileLock.Unlock()
+	activeProfile = p
+}
+
+// ActiveProfile returns the profile set globally.
+func ActiveProfile() Profile {
+	profileLock.RLock()
+	defer profileLock.RUnlock()
+	return activeProfile
+}
+
+// downsample converts colors in attrs which can't be displayed with profile p to the nearest color that
+// can. Attributes which aren't colors are left unchanged.
+func downsample(attrs []Attribute, p Profile) []Attribute {
+	if p == TrueColor {
+		return attrs
+	}
+	out := make([]Attribute, len(attrs))
+	for i := 0; i < len(attrs); i++ {
+		out[i] = downsampleAttribute(attrs[i], p)
+	}
+	return out
+}
+
+func downsampleAttribute(a Attribute, p Profile) Attribute {
+	if p == TrueColor || !a.isExtended() {
+		return a
+	}
+	switch a.kind() {
+	case kindFgRGB, kindBgRGB:
+		r, g, b := a.rgb()
+		n := rgbTo256(r, g, b)
+		if p == ANSI256 {
+			if a.kind() == kindFgRGB {
+				return Fg256(n)
+			}
+			return Bg256(n)
+		}
+		if a.kind() == kindFgRGB {
+			return ansiForeground[rgbTo16(r, g, b)]
+		}
+		return ansiBackground[rgbTo16(r, g, b)]
+	case kindFg256, kindBg256:
+		if p == ANSI256 {
+			return a
+		}
+		n := uint8(a.value())
+		if n >= 16 {
+			n = rgbTo16(paletteRGB(n))
+		}
+		if a.kind() == kindFg256 {
+			return ansiForeground[n]
+		}
+		return ansiBackground[n]
+	case kindUlRGB:
+		// underline colors have no basic form, the first 16 palette entries stand in for it
+		r, g, b := a.rgb()
+		if p == ANSI256 {
+			return Ul256(rgbTo256(r, g, b))
+		}
+		return Ul256(rgbTo16(r, g, b))
+	case kindUl256:
+		if n := uint8(a.value()); p == ANSI && n >= 16 {
+			return Ul256(rgbTo16(paletteRGB(n)))
+		}
+	}
+	return a
+}
+
+// ansiForeground and ansiBackground hold the 16 basic colors in palette order.
+var ansiForeground = [16]Attribute{
+	FgBlack, FgRed, FgGreen, FgYellow, FgBlue, FgMagenta, FgCyan, FgWhite,
+	FgHiBlack, FgHiRed, FgHiGreen, FgHiYellow, FgHiBlue, FgHiMagenta, FgHiCyan, FgHiWhite,
+}
+
+var ansiBackground = [16]Attribute{
+	BgBlack, BgRed, BgGreen, BgYellow, BgBlue, BgMagenta, BgCyan, BgWhite,
+	BgHiBlack, BgHiRed, BgHiGreen, BgHiYellow, BgHiBlue, BgHiMagenta, BgHiCyan, BgHiWhite,
+}
+
+// ansiRGB holds the xterm default values for the 16 basic colors.
+var ansiRGB = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the intensities used by the 6x6x6 color cube of the 256 color palette.
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// paletteRGB returns the RGB value of color n of the 256 color palette.
+func paletteRGB(n uint8) (r, g, b uint8) {
+	switch {
+	case n < 16:
+		c := ansiRGB[n]
+		return c[0], c[1], c[2]
+	case n < 232:
+		n -= 16
+		return cubeLevels[n/36], cubeLevels[(n/6)%6], cubeLevels[n%6]
+	}
+	v := 8 + (n-232)*10
+	return v, v, v
+}
+
+// attributeRGB returns the RGB value of color a, using the xterm values for palette colors. ok is false when
+// a isn't a color.
+func attributeRGB(a Attribute) (r, g, b uint8, ok bool) {
+	switch a.kind() {
+	case kindFgRGB, kindBgRGB, kindUlRGB:
+		r, g, b = a.rgb()
+		return r, g, b, a.isExtended()
+	case kindFg256, kindBg256, kindUl256:
+		r, g, b = paletteRGB(uint8(a.value()))
+		return r, g, b, a.isExtended()
+	}
+	i := paletteIndex(ansiForeground, a)
+	if i < 0 {
+		i = paletteIndex(ansiBackground, a)
+	}
+	if i < 0 {
+		return 0, 0, 0, false
+	}
+	r, g, b = paletteRGB(uint8(i))
+	return r, g, b, true
+}
+
+// rgbTo256 returns the entry of the color cube or grayscale ramp closest to r, g, b. The 16 basic colors
+// are skipped since terminals often redefine them.
+func rgbTo256(r, g, b uint8) uint8 {
+	ri, gi, bi := cubeIndex(r), cubeIndex(g), cubeIndex(b)
+	cube := 16 + 36*ri + 6*gi + bi
+	avg := (int(r) + int(g) + int(b)) / 3
+	gray := uint8(232)
+	if avg > 238 {
+		gray = 255
+	} else if avg > 8 {
+		gray = 232 + uint8((avg-3)/10)
+	}
+	cr, cg, cb := paletteRGB(cube)
+	gr, gg, gb := paletteRGB(gray)
+	if distance(r, g, b, gr, gg, gb) < distance(r, g, b, cr, cg, cb) {
+		return gray
+	}
+	return cube
+}
+
+func cubeIndex(v uint8) uint8 {
+	switch {
+	case v < 48:
+		return 0
+	case v < 115:
+		return 1
+	}
+	return (v - 35) / 40
+}
+
+// rgbTo16 returns the index of the basic color closest to r, g, b.
+func rgbTo16(r, g, b uint8) uint8 {
+	best, bestDist := 0, -1
+	for i, c := range ansiRGB {
+		d := distance(r, g, b, c[0], c[1], c[2])
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return uint8(best)
+}
+
+func distance(r1, g1, b1, r2, g2, b2 uint8) int {
+	dr, dg, db := int(r1)-int(r2), int(g1)-int(g2), int(b1)-int(b2)
+	return dr*dr + dg*dg + db*db
+}
@@ -0,0 +1,100 @@
+package color
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDetectProfile(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		name string
+		env  map[string]string
+		want Profile
+	}{
+		{"empty", map[string]string{}, ANSI},
+		{"dumb", map[string]string{"TERM": "dumb"}, ANSI},
+		{"xterm", map[string]string{"TERM": "xterm"}, ANSI},
+		{"xterm 256", map[string]string{"TERM": "xterm-256color"}, ANSI256},
+		{"screen 256", map[string]string{"TERM": "screen-256color"}, ANSI256},
+		{"colorterm", map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, TrueColor},
+		{"colorterm 24bit", map[string]string{"COLORTERM": "24bit"}, TrueColor},
+		{"direct", map[string]string{"TERM": "xterm-direct"}, TrueColor},
+		{"kitty", map[string]string{"TERM": "xterm-kitty"}, TrueColor},
+		{"windows terminal", map[string]string{"WT_SESSION": "3f2a"}, TrueColor},
+		{"apple terminal", map[string]string{"TERM_PROGRAM": "Apple_Terminal"}, ANSI256},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got := detectProfile(func(key string) string {
+				return tc.env[key]
+			})
+			if got != tc.want {
+				t.Fatalf("want %s got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		name    string
+		attr    Attribute
+		profile Profile
+		want    Attribute
+	}{
+		{"rgb unchanged", FgRGB(1, 2, 3), TrueColor, FgRGB(1, 2, 3)},
+		{"rgb to cube", FgRGB(255, 136, 0), ANSI256, Fg256(208)},
+		{"rgb to gray", BgRGB(128, 128, 130), ANSI256, Bg256(244)},
+		{"rgb to basic", FgRGB(250, 10, 10), ANSI, FgHiRed},
+		{"rgb bg to basic", BgRGB(0, 0, 200), ANSI, BgBlue},
+		{"256 unchanged", Fg256(208), ANSI256, Fg256(208)},
+		{"256 basic", Fg256(1), ANSI, FgRed},
+		{"256 bright", Bg256(12), ANSI, BgHiBlue},
+		{"256 cube", Fg256(196), ANSI, FgHiRed},
+		{"256 gray", Fg256(232), ANSI, FgBlack},
+		{"fixed unchanged", Bold, ANSI, Bold},
+		{"underline rgb to cube", UlRGB(255, 136, 0), ANSI256, Ul256(208)},
+		{"underline rgb to basic", UlRGB(250, 10, 10), ANSI, Ul256(9)},
+		{"underline 256 to basic", Ul256(196), ANSI, Ul256(9)},
+		{"underline basic unchanged", Ul256(4), ANSI, Ul256(4)},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got := downsampleAttribute(tc.attr, tc.profile)
+			if got != tc.want {
+				t.Fatalf("want %s got %s", tc.want.Name(), got.Name())
+			}
+		})
+	}
+}
+
+func TestConsoleProfile(t *testing.T) {
+	t.Parallel()
+	var buff bytes.Buffer
+	cons := newMockConsole(&buff)
+	col := New(Bold, Hex("#ff8800"))
+	_, _ = cons.Print(col, "a")
+	cons.SetProfile(ANSI256)
+	_, _ = cons.Print(col, "b")
+	cons.SetProfile(ANSI)
+	_, _ = cons.Print(col, "c")
+	cons.Set(col)
+	want := "\x1b[1;38;2;255;136;0ma\x1b[0m\x1b[1;38;5;208mb\x1b[0m\x1b[1;33mc\x1b[0m\x1b[1;33m"
+	assertEqualS(t, want, buff.String())
+	if cons.Profile() != ANSI {
+		t.Fatalf("want %s got %s", ANSI, cons.Profile())
+	}
+}
+
+func TestColorSprintProfile(t *testing.T) {
+	// can't be parallel since we're changing the global profile
+	old := ActiveProfile()
+	defer SetProfile(old)
+	col := New(Fg256(208))
+	SetProfile(TrueColor)
+	assertEqualS(t, "\x1b[38;5;208mtext\x1b[0m", col.Sprint("text"))
+	SetProfile(ANSI)
+	assertEqualS(t, "\x1b[33mtext\x1b[0m", col.Sprint("text"))
+}
@@ -0,0 +1,299 @@
+package color
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProgressBar shows the progress of a task on a Console. On a terminal which shows colors the bar is drawn on
+// the last line and redrawn in place, text written to the console by other goroutines appears above it. In
+// other cases a plain line with the percentage is written at most every Interval.
+//
+// The settings must not be changed after the first call to Set or Add. Only one bar or spinner should be
+// active on a console at a time.
+type ProgressBar struct {
+	// Total is the value at which the task is complete. The bar shows a plain count when it's not positive.
+	Total int64
+	// Label is written before the bar.
+	Label string
+	// Width is the number of columns used by the bar itself.
+	Width int
+	// Filled and Empty are the characters of the done and remaining parts.
+	Filled, Empty string
+	// FilledColor, EmptyColor and LabelColor color the parts of the bar. Nil leaves a part uncolored.
+	FilledColor, EmptyColor, LabelColor *Color
+	// Interval is the minimum time between plain lines when the bar isn't redrawn in place.
+	Interval time.Duration
+
+	console  *Console
+	mu       sync.Mutex
+	current  int64
+	started  bool
+	animated bool
+	line     string
+	logged   time.Time
+	percent  int
+}
+
+// NewProgressBar returns a bar for a task of size total writing to c.
+func NewProgressBar(c *Console, total int64, label string) *ProgressBar {
+	return &ProgressBar{
+		Total:       total,
+		Label:       label,
+		Width:       30,
+		Filled:      "█",
+		Empty:       "░",
+		FilledColor: New(FgGreen),
+		EmptyColor:  New(FgHiBlack),
+		Interval:    5 * time.Second,
+		console:     c,
+		percent:     -1,
+	}
+}
+
+// Add advances the bar by n.
+func (b *ProgressBar) Add(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.update(b.current + n)
+}
+
+// Set moves the bar to n.
+func (b *ProgressBar) Set(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.update(n)
+}
+
+// Finish completes the bar. A bar drawn in place is left on its own line, otherwise a final plain line is
+// written.
+func (b *ProgressBar) Finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.started {
+		b.start()
+	}
+	if b.Total > 0 && b.current < b.Total {
+		b.current = b.Total
+	}
+	if b.animated {
+		b.console.endStatus(b.render())
+		return
+	}
+	_, _ = b.console.Write([]byte(b.plain() + lineFeed))
+}
+
+// update sets the current value and redraws the bar if needed, the caller holds the lock.
+func (b *ProgressBar) update(n int64) {
+	if !b.started {
+		b.start()
+	}
+	b.current = n
+	if b.animated {
+		if line := b.render(); line != b.line {
+			b.line = line
+			b.console.drawStatus(line)
+		}
+		return
+	}
+	percent := b.percentDone()
+	if percent != b.percent && time.Since(b.logged) >= b.Interval {
+		b.percent = percent
+		b.logged = time.Now()
+		_, _ = b.console.Write([]byte(b.plain() + lineFeed))
+	}
+}
+
+// start decides whether the bar is redrawn in place.
+func (b *ProgressBar) start() {
+	b.started = true
+	on, _ := b.console.policy()
+	b.animated = on && b.console.IsTerminal()
+}
+
+func (b *ProgressBar) percentDone() int {
+	if b.Total <= 0 {
+		return 0
+	}
+	if b.current >= b.Total {
+		return 100
+	}
+	if b.current <= 0 {
+		return 0
+	}
+	return int(b.current * 100 / b.Total)
+}
+
+// render returns the bar as it's drawn on a terminal.
+func (b *ProgressBar) render() string {
+	_, p := b.console.policy()
+	var s strings.Builder
+	if b.Label != "" {
+		s.WriteString(colorize(b.LabelColor, p, b.Label))
+		s.WriteString(" ")
+	}
+	if b.Total <= 0 {
+		s.WriteString(fmt.Sprint(b.current))
+		return s.String()
+	}
+	width := b.Width
+	if width < 0 {
+		width = 0
+	}
+	filled := width * b.percentDone() / 100
+	s.WriteString(colorize(b.FilledColor, p, strings.Repeat(b.Filled, filled)))
+	s.WriteString(colorize(b.EmptyColor, p, strings.Repeat(b.Empty, width-filled)))
+	s.WriteString(fmt.Sprintf(" %3d%%", b.percentDone()))
+	return s.String()
+}
+
+// plain returns the line written when the bar isn't drawn in place.
+func (b *ProgressBar) plain() string {
+	prefix := ""
+	if b.Label != "" {
+		prefix = b.Label + " "
+	}
+	if b.Total <= 0 {
+		return fmt.Sprintf("%s%d", prefix, b.current)
+	}
+	return fmt.Sprintf("%s%d%% (%d/%d)", prefix, b.percentDone(), b.current, b.Total)
+}
+
+// colorize wraps s with col unless it's nil or s is empty.
+func colorize(col *Color, p Profile, s string) string {
+	if col == nil || s == "" {
+		return s
+	}
+	return col.wrap(p, s)
+}
+
+// Spinner shows that a task is running on a Console. On a terminal which shows colors an animation is drawn
+// on the last line, text written to the console by other goroutines appears above it. In other cases the
+// label is written as a plain line when the spinner starts and when the label changes.
+//
+// The settings must not be changed after Start.
+type Spinner struct {
+	// Frames are shown in turn, one every Interval. Only the label is shown when there are no frames and a
+	// non-positive Interval means the default of 100ms.
+	Frames   []string
+	Interval time.Duration
+	// Color and LabelColor color the animation and the label. Nil leaves a part uncolored.
+	Color, LabelColor *Color
+
+	console  *Console
+	mu       sync.Mutex
+	label    string
+	frame    int
+	animated bool
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+const defaultSpinnerInterval = 100 * time.Millisecond
+
+// NewSpinner returns a spinner writing to c.
+func NewSpinner(c *Console, label string) *Spinner {
+	return &Spinner{
+		Frames:   []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"},
+		Interval: defaultSpinnerInterval,
+		Color:    New(FgCyan),
+		console:  c,
+		label:    label,
+	}
+}
+
+// Start shows the spinner until Stop is called.
+func (s *Spinner) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return
+	}
+	on, _ := s.console.policy()
+	s.animated = on && s.console.IsTerminal()
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	if !s.animated {
+		close(s.done)
+		_, _ = s.console.Write([]byte(s.label + lineFeed))
+		return
+	}
+	s.console.drawStatus(s.render())
+	go s.animate(s.stop, s.done)
+}
+
+// SetLabel changes the text shown next to the spinner.
+func (s *Spinner) SetLabel(label string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if label == s.label {
+		return
+	}
+	s.label = label
+	if s.stop == nil {
+		return
+	}
+	if s.animated {
+		s.console.drawStatus(s.render())
+		return
+	}
+	_, _ = s.console.Write([]byte(label + lineFeed))
+}
+
+// Stop removes the spinner, writing final on its line unless final is empty.
+func (s *Spinner) Stop(final string) {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop = nil
+	s.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+	if s.animated {
+		s.console.endStatus(final)
+		return
+	}
+	if final != "" {
+		_, _ = s.console.Write([]byte(final + lineFeed))
+	}
+}
+
+func (s *Spinner) animate(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	interval := s.Interval
+	if interval <= 0 {
+		interval = defaultSpinnerInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			if len(s.Frames) > 0 {
+				s.frame = (s.frame + 1) % len(s.Frames)
+			}
+			s.console.drawStatus(s.render())
+			s.mu.Unlock()
+		}
+	}
+}
+
+// render returns the spinner line, the caller holds the lock.
+func (s *Spinner) render() string {
+	_, p := s.console.policy()
+	var parts []string
+	if len(s.Frames) > 0 {
+		parts = append(parts, colorize(s.Color, p, s.Frames[s.frame]))
+	}
+	if s.label != "" {
+		parts = append(parts, colorize(s.LabelColor, p, s.label))
+	}
+	return strings.Join(parts, " ")
+}
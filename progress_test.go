@@ -0,0 +1,186 @@
+package color
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTerminalConsole returns a console which behaves as if it wrote to a terminal showing colors.
+func newTerminalConsole(out *bytes.Buffer) *Console {
+	cons := NewConsoleWriter(out, WithMode(Always), WithProfile(TrueColor))
+	cons.terminal = true
+	return cons
+}
+
+func TestProgressBarTerminal(t *testing.T) {
+	t.Parallel()
+	var buff bytes.Buffer
+	cons := newTerminalConsole(&buff)
+	bar := NewProgressBar(cons, 4, "deploy")
+	bar.Width = 4
+	bar.Filled, bar.Empty = "#", "."
+	bar.FilledColor, bar.EmptyColor = New(FgGreen), nil
+	bar.Add(1)
+	bar.Add(0)
+	_, _ = cons.Println(New(FgRed), "log")
+	bar.Set(3)
+	bar.Finish()
+
+	want := "\r\x1b[2Kdeploy \x1b[32m#\x1b[0m...  25%" +
+		"\r\x1b[2K\x1b[31mlog\x1b[0m\ndeploy \x1b[32m#\x1b[0m...  25%" +
+		"\r\x1b[2Kdeploy \x1b[32m###\x1b[0m.  75%" +
+		"\r\x1b[2Kdeploy \x1b[32m####\x1b[0m 100%\n"
+	assertEqualS(t, want, buff.String())
+
+	buff.Reset()
+	_, _ = cons.Write([]byte("after\n"))
+	assertEqualS(t, "after\n", buff.String())
+}
+
+func TestProgressBarPlain(t *testing.T) {
+	t.Parallel()
+	var buff bytes.Buffer
+	cons := NewConsoleWriter(&buff, WithMode(Always))
+	bar := NewProgressBar(cons, 200, "upload")
+	bar.Interval = 0
+	bar.Add(1)
+	bar.Add(1)
+	bar.Set(100)
+	bar.Finish()
+	want := "upload 0% (1/200)\n" +
+		"upload 1% (2/200)\n" +
+		"upload 50% (100/200)\n" +
+		"upload 100% (200/200)\n"
+	assertEqualS(t, want, buff.String())
+
+	buff.Reset()
+	bar = NewProgressBar(cons, 0, "")
+	bar.Interval = time.Hour
+	bar.Add(5)
+	bar.Add(5)
+	bar.Finish()
+	assertEqualS(t, "5\n10\n", buff.String())
+}
+
+func TestSpinner(t *testing.T) {
+	t.Parallel()
+	var buff bytes.Buffer
+	cons := newTerminalConsole(&buff)
+	s := NewSpinner(cons, "building")
+	s.Frames = []string{"-", "+"}
+	s.Interval = time.Hour
+	s.Color = nil
+	s.Start()
+	s.SetLabel("pushing")
+	s.Stop("done")
+	assertEqualS(t, "\r\x1b[2K- building\r\x1b[2K- pushing\r\x1b[2Kdone\n", buff.String())
+
+	buff.Reset()
+	cons.SetMode(Never)
+	s = NewSpinner(cons, "building")
+	s.Start()
+	s.SetLabel("pushing")
+	s.Stop("")
+	assertEqualS(t, "building\npushing\n", buff.String())
+}
+
+func TestSpinnerPartialLines(t *testing.T) {
+	t.Parallel()
+	var buff bytes.Buffer
+	cons := newTerminalConsole(&buff)
+	s := NewSpinner(cons, "working")
+	s.Frames = []string{"-"}
+	s.Interval = time.Hour
+	s.Color = nil
+	s.Start()
+	_, _ = cons.Write([]byte("uploading "))
+	_, _ = cons.Write([]byte("done\nnext "))
+	_, _ = cons.Write([]byte("part"))
+	s.Stop("finished")
+	want := "\r\x1b[2K- working" +
+		"\r\x1b[2Kuploading done\n- working" +
+		"\r\x1b[2Knext part\nfinished\n"
+	assertEqualS(t, want, buff.String())
+}
+
+func TestSpinnerModeNever(t *testing.T) {
+	t.Parallel()
+	var buff bytes.Buffer
+	cons := newTerminalConsole(&buff)
+	s := NewSpinner(cons, "working")
+	s.Frames = []string{"-"}
+	s.Interval = time.Hour
+	s.Color = nil
+	s.Start()
+	buff.Reset()
+	cons.SetMode(Never)
+	s.SetLabel("still working")
+	_, _ = cons.Write([]byte("log\n"))
+	s.Stop("done")
+	assertEqualS(t, "log\ndone\n", buff.String())
+}
+
+func TestSpinnerDefaults(t *testing.T) {
+	t.Parallel()
+	var buff bytes.Buffer
+	cons := newTerminalConsole(&buff)
+	s := NewSpinner(cons, "working")
+	s.Frames = nil
+	s.Interval = 0
+	s.Start()
+	// the spinner redraws the label once the default interval has passed
+	redrawn := false
+	for deadline := time.Now().Add(5 * time.Second); !redrawn && time.Now().Before(deadline); {
+		time.Sleep(defaultSpinnerInterval)
+		cons.Lock()
+		redrawn = strings.HasPrefix(buff.String(), "\r\x1b[2Kworking\r\x1b[2Kworking")
+		cons.Unlock()
+	}
+	s.Stop("")
+	if !redrawn {
+		t.Fatalf("expected the label to be redrawn got %q", buff.String())
+	}
+}
+
+func TestProgressBarNegativeWidth(t *testing.T) {
+	t.Parallel()
+	var buff bytes.Buffer
+	cons := newTerminalConsole(&buff)
+	bar := NewProgressBar(cons, 2, "")
+	bar.Width = -5
+	bar.Add(1)
+	assertEqualS(t, "\r\x1b[2K  50%", buff.String())
+}
+
+func TestSpinnerConcurrentWrites(t *testing.T) {
+	t.Parallel()
+	var buff bytes.Buffer
+	cons := newTerminalConsole(&buff)
+	s := NewSpinner(cons, "working")
+	s.Interval = time.Millisecond
+	s.Start()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				_, _ = cons.Write([]byte("line\n"))
+			}
+		}()
+	}
+	wg.Wait()
+	s.Stop("")
+	for _, line := range strings.Split(Strip(buff.String()), "\n") {
+		line = line[strings.LastIndex(line, "\r")+1:]
+		if line != "line" && line != "" && !strings.HasSuffix(line, " working") {
+			t.Fatalf("corrupted line %q", line)
+		}
+	}
+	if n := strings.Count(buff.String(), "line\n"); n != 80 {
+		t.Fatalf("expected 80 lines got %d", n)
+	}
+}
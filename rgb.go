@@ -0,0 +1,214 @@
+package color
+
+import (
+	"fmt"
+	"math"
+)
+
+// RGB is a 24 bit sRGB color value. It converts between color spaces and derives related colors, the
+// result is used in styles through Fg, Bg or Ul.
+type RGB struct {
+	R, G, B uint8
+}
+
+// ParseRGB parses a hex color, #rrggbb or #rgb with an optional #.
+func ParseRGB(s string) (RGB, error) {
+	r, g, b, ok := parseHex(s)
+	if !ok {
+		return RGB{}, fmt.Errorf("invalid hex color %q, expected #rrggbb or #rgb", s)
+	}
+	return RGB{r, g, b}, nil
+}
+
+// AttributeRGB returns the value of color a. Palette colors use the values of xterm, terminals may show
+// them differently. ok is false when a isn't a color.
+func AttributeRGB(a Attribute) (c RGB, ok bool) {
+	r, g, b, ok := attributeRGB(a)
+	return RGB{r, g, b}, ok
+}
+
+// HSL returns the color with hue h in degrees, saturation s and lightness l between 0 and 1.
+func HSL(h, s, l float64) RGB {
+	s, l = clamp01(s), clamp01(l)
+	c := (1 - math.Abs(2*l-1)) * s
+	return hueToRGB(h, c, l-c/2)
+}
+
+// HSV returns the color with hue h in degrees, saturation s and value v between 0 and 1.
+func HSV(h, s, v float64) RGB {
+	s, v = clamp01(s), clamp01(v)
+	c := v * s
+	return hueToRGB(h, c, v-c)
+}
+
+// Lab returns the color with CIELAB lightness l from 0 to 100 and components a and b, using the D65 white
+// point. Colors outside of the sRGB gamut are clamped.
+func Lab(l, a, b float64) RGB {
+	r, g, bl := lab{l, a, b}.rgb()
+	return RGB{r, g, bl}
+}
+
+// hueToRGB converts hue h with chroma c to RGB, adding m to each component.
+func hueToRGB(h, c, m float64) RGB {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	return RGB{toByte(r + m), toByte(g + m), toByte(b + m)}
+}
+
+// HSL returns the hue in degrees, saturation and lightness of c.
+func (c RGB) HSL() (h, s, l float64) {
+	h, hi, lo := c.hue()
+	l = (hi + lo) / 2
+	if d := hi - lo; d > 0 {
+		s = d / (1 - math.Abs(2*l-1))
+	}
+	return h, s, l
+}
+
+// HSV returns the hue in degrees, saturation and value of c.
+func (c RGB) HSV() (h, s, v float64) {
+	h, hi, lo := c.hue()
+	if hi > 0 {
+		s = (hi - lo) / hi
+	}
+	return h, s, hi
+}
+
+// hue returns the hue of c in degrees with its largest and smallest component between 0 and 1.
+func (c RGB) hue() (h, hi, lo float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	hi, lo = math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	d := hi - lo
+	switch {
+	case d == 0:
+		h = 0
+	case hi == r:
+		h = math.Mod((g-b)/d, 6)
+	case hi == g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h, hi, lo
+}
+
+// Lab returns the CIELAB lightness and a, b components of c.
+func (c RGB) Lab() (l, a, b float64) {
+	v := rgbToLab(c.R, c.G, c.B)
+	return v.l, v.a, v.b
+}
+
+// Hex returns c as #rrggbb.
+func (c RGB) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// String returns c as #rrggbb.
+func (c RGB) String() string {
+	return c.Hex()
+}
+
+// Fg returns an Attribute setting the foreground to c.
+func (c RGB) Fg() Attribute {
+	return FgRGB(c.R, c.G, c.B)
+}
+
+// Bg returns an Attribute setting the background to c.
+func (c RGB) Bg() Attribute {
+	return BgRGB(c.R, c.G, c.B)
+}
+
+// Ul returns an Attribute setting the underline color to c.
+func (c RGB) Ul() Attribute {
+	return UlRGB(c.R, c.G, c.B)
+}
+
+// Lighten returns c with its HSL lightness increased by amount, for example 0.1 for ten percent.
+func (c RGB) Lighten(amount float64) RGB {
+	h, s, l := c.HSL()
+	return HSL(h, s, l+amount)
+}
+
+// Darken returns c with its HSL lightness decreased by amount.
+func (c RGB) Darken(amount float64) RGB {
+	return c.Lighten(-amount)
+}
+
+// Saturate returns c with its HSL saturation increased by amount.
+func (c RGB) Saturate(amount float64) RGB {
+	h, s, l := c.HSL()
+	return HSL(h, s+amount, l)
+}
+
+// Desaturate returns c with its HSL saturation decreased by amount.
+func (c RGB) Desaturate(amount float64) RGB {
+	return c.Saturate(-amount)
+}
+
+// Mix blends c with o in the CIELAB space, t = 0 returns c and t = 1 returns o. Gradients blend the same
+// way.
+func (c RGB) Mix(o RGB, t float64) RGB {
+	r, g, b := rgbToLab(c.R, c.G, c.B).mix(rgbToLab(o.R, o.G, o.B), clamp01(t)).rgb()
+	return RGB{r, g, b}
+}
+
+// Luminance returns the relative luminance of c as defined by WCAG, from 0 for black to 1 for white.
+func (c RGB) Luminance() float64 {
+	return 0.2126*srgbToLinear(c.R) + 0.7152*srgbToLinear(c.G) + 0.0722*srgbToLinear(c.B)
+}
+
+// ContrastRatio returns the WCAG contrast ratio of c and o, from 1 for equal colors to 21 for black and
+// white. WCAG AA asks for at least 4.5 for normal text and 3 for large text.
+func (c RGB) ContrastRatio(o RGB) float64 {
+	l1, l2 := c.Luminance(), o.Luminance()
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+// Nearest16 returns the foreground color of the 16 color palette closest to c.
+func (c RGB) Nearest16() Attribute {
+	return ansiForeground[rgbTo16(c.R, c.G, c.B)]
+}
+
+// Nearest256 returns the foreground color of the 256 color palette closest to c. Like the conversion for
+// 256 color consoles it picks from the color cube and grayscale ramp, skipping the first 16 colors which
+// terminals often redefine.
+func (c RGB) Nearest256() Attribute {
+	return Fg256(rgbTo256(c.R, c.G, c.B))
+}
+
+// clamp01 limits v to the range from 0 to 1, NaN becomes 0.
+func clamp01(v float64) float64 {
+	if math.IsNaN(v) {
+		return 0
+	}
+	return math.Max(0, math.Min(1, v))
+}
+
+func toByte(v float64) uint8 {
+	return uint8(math.Round(clamp01(v) * 255))
+}
@@ -0,0 +1,127 @@
+package color
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRGBConversions(t *testing.T) {
+	t.Parallel()
+	orange := RGB{255, 136, 0}
+	h, s, l := orange.HSL()
+	assertClose(t, 32, h, 0.1)
+	assertClose(t, 1, s, 0.001)
+	assertClose(t, 0.5, l, 0.001)
+	if got := HSL(h, s, l); got != orange {
+		t.Fatalf("want %s got %s", orange, got)
+	}
+
+	h, s, v := orange.HSV()
+	assertClose(t, 32, h, 0.1)
+	assertClose(t, 1, s, 0.001)
+	assertClose(t, 1, v, 0.001)
+	if got := HSV(h, s, v); got != orange {
+		t.Fatalf("want %s got %s", orange, got)
+	}
+
+	L, a, b := RGB{255, 0, 0}.Lab()
+	assertClose(t, 53.24, L, 0.01)
+	assertClose(t, 80.09, a, 0.01)
+	assertClose(t, 67.20, b, 0.01)
+	if got := Lab(L, a, b); got != (RGB{255, 0, 0}) {
+		t.Fatalf("want red got %s", got)
+	}
+
+	for _, c := range []RGB{{0, 0, 0}, {255, 255, 255}, {18, 52, 86}, {128, 128, 128}, {10, 200, 30}} {
+		if got := HSL(c.HSL()); got != c {
+			t.Fatalf("HSL: want %s got %s", c, got)
+		}
+		if got := HSV(c.HSV()); got != c {
+			t.Fatalf("HSV: want %s got %s", c, got)
+		}
+	}
+	if got := HSL(-120, 1, 0.5); got != (RGB{0, 0, 255}) {
+		t.Fatalf("expected negative hues to wrap got %s", got)
+	}
+}
+
+func TestRGBParse(t *testing.T) {
+	t.Parallel()
+	c, err := ParseRGB("#f80")
+	if err != nil || c != (RGB{255, 136, 0}) {
+		t.Fatalf("unexpected %s %v", c, err)
+	}
+	assertEqualS(t, "#ff8800", c.Hex())
+	if c.Fg() != Hex("#ff8800") || c.Bg() != BgHex("#ff8800") || c.Ul() != UlRGB(255, 136, 0) {
+		t.Fatal("unexpected attributes")
+	}
+	if _, err := ParseRGB("orange"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if c, ok := AttributeRGB(Bg256(196)); !ok || c != (RGB{255, 0, 0}) {
+		t.Fatalf("unexpected %s %v", c, ok)
+	}
+	if _, ok := AttributeRGB(Bold); ok {
+		t.Fatal("expected Bold not to be a color")
+	}
+}
+
+func TestRGBOperations(t *testing.T) {
+	t.Parallel()
+	red := RGB{255, 0, 0}
+	tt := []struct {
+		name string
+		got  RGB
+		want RGB
+	}{
+		{"lighten", red.Lighten(0.25), RGB{255, 128, 128}},
+		{"darken", red.Darken(0.25), RGB{128, 0, 0}},
+		{"lighten clamps", red.Lighten(2), RGB{255, 255, 255}},
+		{"desaturate", red.Desaturate(1), RGB{128, 128, 128}},
+		{"saturate", RGB{191, 64, 64}.Saturate(0.5), RGB{255, 0, 0}},
+		{"mix start", red.Mix(RGB{0, 0, 255}, 0), red},
+		{"mix end", red.Mix(RGB{0, 0, 255}, 1), RGB{0, 0, 255}},
+		{"mix gray", RGB{0, 0, 0}.Mix(RGB{255, 255, 255}, 0.5), RGB{119, 119, 119}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.got != tc.want {
+				t.Fatalf("want %s got %s", tc.want, tc.got)
+			}
+		})
+	}
+}
+
+func TestRGBContrast(t *testing.T) {
+	t.Parallel()
+	black, white := RGB{0, 0, 0}, RGB{255, 255, 255}
+	assertClose(t, 21, black.ContrastRatio(white), 0.001)
+	assertClose(t, 21, white.ContrastRatio(black), 0.001)
+	assertClose(t, 1, white.ContrastRatio(white), 0.001)
+	// #767676 is the lightest gray passing WCAG AA on white
+	assertClose(t, 4.54, RGB{0x76, 0x76, 0x76}.ContrastRatio(white), 0.01)
+	assertClose(t, 0.2126, RGB{255, 0, 0}.Luminance(), 0.0001)
+}
+
+func TestRGBNearest(t *testing.T) {
+	t.Parallel()
+	if got := (RGB{250, 10, 10}).Nearest16(); got != FgHiRed {
+		t.Fatalf("want FgHiRed got %s", got.Name())
+	}
+	if got := (RGB{0, 0, 200}).Nearest16(); got != FgBlue {
+		t.Fatalf("want FgBlue got %s", got.Name())
+	}
+	if got := (RGB{255, 136, 0}).Nearest256(); got != Fg256(208) {
+		t.Fatalf("want Fg256(208) got %s", got.Name())
+	}
+	if got := (RGB{128, 128, 130}).Nearest256(); got != Fg256(244) {
+		t.Fatalf("want Fg256(244) got %s", got.Name())
+	}
+}
+
+func assertClose(t *testing.T, want, got, delta float64) {
+	t.Helper()
+	if math.Abs(want-got) > delta {
+		t.Fatalf("want %v got %v", want, got)
+	}
+}
@@ -0,0 +1,128 @@
+package color
+
+import (
+	"io"
+	"sync"
+)
+
+// scanState tracks where a scanner is within an escape sequence. Sequences may be split across writes, so
+// the state is kept between calls.
+type scanState int
+
+const (
+	stateText scanState = iota
+	// stateEscape follows ESC.
+	stateEscape
+	// stateEscapeIntermediate follows ESC and one or more intermediate bytes, as in ESC ( B.
+	stateEscapeIntermediate
+	// stateCSI is inside a control sequence such as an SGR code, ESC [ ... final byte.
+	stateCSI
+	// stateString is inside an OSC, DCS, SOS, PM or APC string which ends with BEL or ST.
+	stateString
+	// stateStringEscape follows ESC inside a string, the start of ST (ESC \).
+	stateStringEscape
+)
+
+const (
+	esc = 0x1b
+	bel = 0x07
+)
+
+// stripper removes escape sequences from text.
+type stripper struct {
+	state scanState
+}
+
+// strip appends the bytes of src which aren't part of an escape sequence to dst.
+func (s *stripper) strip(dst, src []byte) []byte {
+	for _, b := range src {
+		if s.step(b) {
+			dst = append(dst, b)
+		}
+	}
+	return dst
+}
+
+// step advances the state by one byte and reports whether the byte is visible text.
+func (s *stripper) step(b byte) bool {
+	switch s.state {
+	case stateText:
+		if b == esc {
+			s.state = stateEscape
+			return false
+		}
+		return true
+	case stateEscape:
+		switch {
+		case b == '[':
+			s.state = stateCSI
+		case b == ']' || b == 'P' || b == 'X' || b == '^' || b == '_':
+			s.state = stateString
+		case b >= 0x20 && b <= 0x2f:
+			s.state = stateEscapeIntermediate
+		case b == esc:
+			// a lone ESC followed by another sequence
+		default:
+			s.state = stateText
+		}
+	case stateEscapeIntermediate:
+		if b < 0x20 || b > 0x2f {
+			s.state = stateText
+		}
+	case stateCSI:
+		if b >= 0x40 && b <= 0x7e {
+			s.state = stateText
+		}
+	case stateString:
+		switch b {
+		case bel:
+			s.state = stateText
+		case esc:
+			s.state = stateStringEscape
+		}
+	case stateStringEscape:
+		switch b {
+		case '\\':
+			s.state = stateText
+		case esc:
+		default:
+			s.state = stateString
+		}
+	}
+	return false
+}
+
+// Strip removes ANSI escape sequences from s. Besides SGR codes written by this package it removes other
+// control sequences such as cursor movement, and OSC strings such as hyperlinks.
+func Strip(s string) string {
+	var st stripper
+	return string(st.strip(make([]byte, 0, len(s)), []byte(s)))
+}
+
+// StripWriter removes ANSI escape sequences from everything written to it before passing the text on. Escape
+// sequences may be split across calls to Write.
+type StripWriter struct {
+	mu  sync.Mutex
+	out io.Writer
+	st  stripper
+	buf []byte
+}
+
+// NewStripWriter returns a StripWriter writing to out.
+func NewStripWriter(out io.Writer) *StripWriter {
+	return &StripWriter{out: out}
+}
+
+// Write strips escape sequences from p and writes the remaining text. It returns len(p) when the text was
+// written, since the number of bytes passed on may differ.
+func (w *StripWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = w.st.strip(w.buf[:0], p)
+	if len(w.buf) > 0 {
+		if _, err := w.out.Write(w.buf); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,77 @@
+package color
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestStrip(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "hello", "hello"},
+		{"sgr", "\x1b[31mred\x1b[0m", "red"},
+		{"chained sgr", "\x1b[1;4;38;2;255;136;0mfancy\x1b[0m text", "fancy text"},
+		{"cursor", "\x1b[2K\x1b[1Aup\x1b[?25l", "up"},
+		{"osc bel", "\x1b]0;title\x07text", "text"},
+		{"osc st", "\x1b]8;;https://heroku.com\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"charset", "\x1b(Bascii", "ascii"},
+		{"two byte", "\x1b7saved\x1b8", "saved"},
+		{"unicode", "\x1b[32m日本語\x1b[0m ✓", "日本語 ✓"},
+		{"unterminated", "text\x1b[31", "text"},
+		{"color sprint", New(FgRed, Bold).wrap(TrueColor, "bold red"), "bold red"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assertEqualS(t, tc.want, Strip(tc.in))
+		})
+	}
+}
+
+func TestStripWriter(t *testing.T) {
+	t.Parallel()
+	var buff bytes.Buffer
+	w := NewStripWriter(&buff)
+	in := "\x1b[31mred\x1b[0m \x1b]8;;http://example.com\x1b\\link\x1b]8;;\x1b\\ done"
+	// write one byte at a time so every sequence is split across writes
+	for i := 0; i < len(in); i++ {
+		n, err := w.Write([]byte{in[i]})
+		if err != nil {
+			t.Fatal("no error expected", err)
+		}
+		if n != 1 {
+			t.Fatalf("expected to consume 1 byte got %d", n)
+		}
+	}
+	assertEqualS(t, "red link done", buff.String())
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("closed")
+}
+
+func TestStripWriterError(t *testing.T) {
+	t.Parallel()
+	w := NewStripWriter(failingWriter{})
+	if n, err := w.Write([]byte("foo")); err == nil || n != 0 {
+		t.Fatalf("expected an error got %d, %v", n, err)
+	}
+	// nothing visible to write, so the underlying writer isn't called
+	if _, err := w.Write([]byte("\x1b[0m")); err != nil {
+		t.Fatal("no error expected", err)
+	}
+}
+
+func TestConsoleStripsOSC(t *testing.T) {
+	t.Parallel()
+	var buff bytes.Buffer
+	cons := NewConsoleWriter(&buff, WithMode(Never))
+	_, _ = cons.Write([]byte("\x1b]8;;http://example.com\x1b\\link\x1b]8;;\x1b\\"))
+	assertEqualS(t, "link", buff.String())
+}
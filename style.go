@@ -0,0 +1,363 @@
+package color
+
+import "strings"
+
+// decorationOrder lists the attributes which can be combined freely in the order they are written, as opposed
+// to colors where only one foreground and one background apply. The underline styles are the exception, only
+// one of them applies.
+var decorationOrder = []Attribute{
+	Bold, Faint, Italic, Underline, BlinkSlow, BlinkRapid, ReverseVideo, Concealed, CrossedOut,
+	DoubleUnderline, CurlyUnderline, DottedUnderline, Overline,
+}
+
+// underlineStyles lists the decorations which replace each other.
+var underlineStyles = []Attribute{Underline, DoubleUnderline, CurlyUnderline, DottedUnderline}
+
+// attrSet is a set of decorations or of attributes turning others off, with one bit for each entry of
+// decorationOrder or offAttributes. Attributes can't be combined into a mask themselves since some of them
+// are extended attributes.
+type attrSet uint32
+
+// decorationSet returns the set holding the decorations attrs.
+func decorationSet(attrs ...Attribute) attrSet {
+	var set attrSet
+	for _, a := range attrs {
+		for i, d := range decorationOrder {
+			if d == a {
+				set |= 1 << uint(i)
+			}
+		}
+	}
+	return set
+}
+
+var underlines = decorationSet(underlineStyles...)
+
+// offAttributes lists the attributes which turn off others in the order they are written, with the
+// decorations each of them turns off.
+var offAttributes = []struct {
+	off   Attribute
+	turns attrSet
+}{
+	{NormalIntensity, decorationSet(Bold, Faint)},
+	{NotItalic, decorationSet(Italic)},
+	{NoUnderline, underlines},
+	{NotBlinking, decorationSet(BlinkSlow, BlinkRapid)},
+	{NotReversed, decorationSet(ReverseVideo)},
+	{Revealed, decorationSet(Concealed)},
+	{NotCrossedOut, decorationSet(CrossedOut)},
+	{NotOverlined, decorationSet(Overline)},
+	{DefaultFg, 0},
+	{DefaultBg, 0},
+}
+
+// offSet returns the set holding the attribute a which turns others off.
+func offSet(a Attribute) attrSet {
+	for i, o := range offAttributes {
+		if o.off == a {
+			return 1 << uint(i)
+		}
+	}
+	return 0
+}
+
+// Style describes the appearance of text with at most one foreground, background and underline color plus a
+// set of decorations such as Bold or Underline. A style may also turn attributes off, for example with
+// NotItalic, so it can be applied on top of other colored text. Styles are values, the methods return a
+// modified copy and never change the receiver. Two styles can be compared with ==.
+type Style struct {
+	fg          Attribute
+	bg          Attribute
+	ul          Attribute
+	decorations attrSet
+	// offs holds the attributes which turn others off, they are written before all other attributes.
+	offs attrSet
+}
+
+// NewStyle returns an empty style which doesn't change the appearance of text.
+func NewStyle() Style {
+	return Style{}
+}
+
+// Fg returns a copy of s with the foreground set to color a. Background colors are converted to the matching
+// foreground color. Attributes which aren't colors are ignored.
+func (s Style) Fg(a Attribute) Style {
+	if fg, ok := toForeground(a); ok {
+		s.fg = fg
+	}
+	return s
+}
+
+// Bg returns a copy of s with the background set to color a. Foreground colors are converted to the matching
+// background color. Attributes which aren't colors are ignored.
+func (s Style) Bg(a Attribute) Style {
+	if bg, ok := toBackground(a); ok {
+		s.bg = bg
+	}
+	return s
+}
+
+// Ul returns a copy of s with the underline color set to color a. Foreground and background colors are
+// converted to the matching underline color. Attributes which aren't colors are ignored.
+func (s Style) Ul(a Attribute) Style {
+	if ul, ok := toUnderline(a); ok {
+		s.ul = ul
+	}
+	return s
+}
+
+// Bold returns a copy of s with bold text.
+func (s Style) Bold() Style { return s.with(Bold) }
+
+// Faint returns a copy of s with faint text.
+func (s Style) Faint() Style { return s.with(Faint) }
+
+// Italic returns a copy of s with italic text.
+func (s Style) Italic() Style { return s.with(Italic) }
+
+// Underline returns a copy of s with underlined text.
+func (s Style) Underline() Style { return s.with(Underline) }
+
+// Blink returns a copy of s with slowly blinking text.
+func (s Style) Blink() Style { return s.with(BlinkSlow) }
+
+// RapidBlink returns a copy of s with rapidly blinking text.
+func (s Style) RapidBlink() Style { return s.with(BlinkRapid) }
+
+// Reverse returns a copy of s with foreground and background swapped.
+func (s Style) Reverse() Style { return s.with(ReverseVideo) }
+
+// Conceal returns a copy of s with hidden text.
+func (s Style) Conceal() Style { return s.with(Concealed) }
+
+// CrossedOut returns a copy of s with crossed out text.
+func (s Style) CrossedOut() Style { return s.with(CrossedOut) }
+
+// DoubleUnderline returns a copy of s with double underlined text.
+func (s Style) DoubleUnderline() Style { return s.with(DoubleUnderline) }
+
+// CurlyUnderline returns a copy of s with text underlined by a curly line.
+func (s Style) CurlyUnderline() Style { return s.with(CurlyUnderline) }
+
+// DottedUnderline returns a copy of s with text underlined by a dotted line.
+func (s Style) DottedUnderline() Style { return s.with(DottedUnderline) }
+
+// Overline returns a copy of s with a line above the text.
+func (s Style) Overline() Style { return s.with(Overline) }
+
+// With returns a copy of s with attributes applied in order, the way a terminal applies them. Colors replace
+// the current foreground, background or underline color, the underline styles replace each other and the
+// other decorations are combined. Attributes such as NotItalic or DefaultFg turn off what was set before them
+// and are kept so they are written as well. Reset clears the style.
+func (s Style) With(attrs ...Attribute) Style {
+	for _, a := range attrs {
+		switch {
+		case a == Reset:
+			s = Style{}
+		case isForeground(a):
+			s.fg = a
+			s.offs &^= offSet(DefaultFg)
+		case isBackground(a):
+			s.bg = a
+			s.offs &^= offSet(DefaultBg)
+		case isUnderlineColor(a):
+			s.ul = a
+		case decorationSet(a) != 0:
+			s = s.with(a)
+		default:
+			s = s.off(a)
+		}
+	}
+	return s
+}
+
+// with adds decoration a, dropping attributes which are made redundant by it.
+func (s Style) with(a Attribute) Style {
+	bit := decorationSet(a)
+	if bit&underlines != 0 {
+		s.decorations &^= underlines
+	}
+	s.decorations |= bit
+	for i, o := range offAttributes {
+		// turning a decoration on undoes the off attribute, unless it turns off others as well
+		if o.turns == bit || (bit&underlines != 0 && o.off == NoUnderline) {
+			s.offs &^= 1 << uint(i)
+		}
+	}
+	return s
+}
+
+// off applies the attribute a which turns other attributes off.
+func (s Style) off(a Attribute) Style {
+	for i, o := range offAttributes {
+		if o.off != a {
+			continue
+		}
+		s.decorations &^= o.turns
+		switch a {
+		case DefaultFg:
+			s.fg = 0
+		case DefaultBg:
+			s.bg = 0
+		}
+		s.offs |= 1 << uint(i)
+	}
+	return s
+}
+
+// without removes the decorations attrs.
+func (s Style) without(attrs ...Attribute) Style {
+	s.decorations &^= decorationSet(attrs...)
+	return s
+}
+
+// Foreground returns the foreground color of s, or 0 when it isn't set.
+func (s Style) Foreground() Attribute {
+	return s.fg
+}
+
+// Background returns the background color of s, or 0 when it isn't set.
+func (s Style) Background() Attribute {
+	return s.bg
+}
+
+// Has reports whether the decoration a, for example Bold, is part of s.
+func (s Style) Has(a Attribute) bool {
+	bit := decorationSet(a)
+	return bit != 0 && s.decorations&bit != 0
+}
+
+// UnderlineColor returns the underline color of s, or 0 when it isn't set.
+func (s Style) UnderlineColor() Attribute {
+	return s.ul
+}
+
+// IsZero reports whether s leaves the appearance of text unchanged.
+func (s Style) IsZero() bool {
+	return s == Style{}
+}
+
+// Merge returns s overridden by o. The colors of o replace those of s when they are set and the decorations
+// of both are combined. Attributes of o which turn others off, such as NotItalic, remove them from s. It's
+// used to derive a style from a base style.
+func (s Style) Merge(o Style) Style {
+	return s.With(o.Attributes()...)
+}
+
+// Attributes returns the attributes making up s. Attributes turning others off come first, followed by the
+// decorations, the foreground, background and underline color.
+func (s Style) Attributes() []Attribute {
+	var attrs []Attribute
+	for i, o := range offAttributes {
+		if s.offs&(1<<uint(i)) != 0 {
+			attrs = append(attrs, o.off)
+		}
+	}
+	for i, a := range decorationOrder {
+		if s.decorations&(1<<uint(i)) != 0 {
+			attrs = append(attrs, a)
+		}
+	}
+	if s.fg != 0 {
+		attrs = append(attrs, s.fg)
+	}
+	if s.bg != 0 {
+		attrs = append(attrs, s.bg)
+	}
+	if s.ul != 0 {
+		attrs = append(attrs, s.ul)
+	}
+	return attrs
+}
+
+// Color returns the Color which renders s.
+func (s Style) Color() *Color {
+	return New(s.Attributes()...)
+}
+
+// String returns the names of the attributes of s.
+func (s Style) String() string {
+	attrs := s.Attributes()
+	names := make([]string, len(attrs))
+	for i, a := range attrs {
+		names[i] = a.Name()
+	}
+	return "Style(" + strings.Join(names, ", ") + ")"
+}
+
+func isForeground(a Attribute) bool {
+	if a.isExtended() {
+		return a.kind() == kindFg256 || a.kind() == kindFgRGB
+	}
+	return paletteIndex(ansiForeground, a) >= 0
+}
+
+func isBackground(a Attribute) bool {
+	if a.isExtended() {
+		return a.kind() == kindBg256 || a.kind() == kindBgRGB
+	}
+	return paletteIndex(ansiBackground, a) >= 0
+}
+
+func isUnderlineColor(a Attribute) bool {
+	return a.isExtended() && (a.kind() == kindUl256 || a.kind() == kindUlRGB)
+}
+
+func paletteIndex(palette [16]Attribute, a Attribute) int {
+	for i, p := range palette {
+		if p == a {
+			return i
+		}
+	}
+	return -1
+}
+
+// toForeground returns the foreground color matching color a.
+func toForeground(a Attribute) (Attribute, bool) {
+	switch {
+	case isForeground(a):
+		return a, true
+	case !isBackground(a):
+		return 0, false
+	case a.kind() == kindBg256:
+		return Fg256(uint8(a.value())), true
+	case a.kind() == kindBgRGB:
+		return FgRGB(a.rgb()), true
+	}
+	return ansiForeground[paletteIndex(ansiBackground, a)], true
+}
+
+// toUnderline returns the underline color matching color a. The basic colors map to the first 16 entries
+// of the 256 color palette.
+func toUnderline(a Attribute) (Attribute, bool) {
+	switch {
+	case isUnderlineColor(a):
+		return a, true
+	case a.isExtended() && (a.kind() == kindFg256 || a.kind() == kindBg256):
+		return Ul256(uint8(a.value())), true
+	case a.isExtended() && (a.kind() == kindFgRGB || a.kind() == kindBgRGB):
+		return UlRGB(a.rgb()), true
+	}
+	if i := paletteIndex(ansiForeground, a); i >= 0 {
+		return Ul256(uint8(i)), true
+	}
+	if i := paletteIndex(ansiBackground, a); i >= 0 {
+		return Ul256(uint8(i)), true
+	}
+	return 0, false
+}
+
+// toBackground returns the background color matching color a.
+func toBackground(a Attribute) (Attribute, bool) {
+	switch {
+	case isBackground(a):
+		return a, true
+	case !isForeground(a):
+		return 0, false
+	case a.kind() == kindFg256:
+		return Bg256(uint8(a.value())), true
+	case a.kind() == kindFgRGB:
+		return BgRGB(a.rgb()), true
+	}
+	return ansiBackground[paletteIndex(ansiForeground, a)], true
+}
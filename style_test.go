@@ -0,0 +1,122 @@
+package color
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStyleBuilder(t *testing.T) {
+	t.Parallel()
+	s := NewStyle().Fg(FgRed).Bg(BgBlack).Bold().Underline()
+	want := []Attribute{Bold, Underline, FgRed, BgBlack}
+	if got := s.Attributes(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v got %v", want, got)
+	}
+	if s.Foreground() != FgRed || s.Background() != BgBlack {
+		t.Fatalf("unexpected colors %s", s)
+	}
+	if !s.Has(Bold) || !s.Has(Underline) || s.Has(Italic) {
+		t.Fatalf("unexpected decorations %s", s)
+	}
+	assertEqualS(t, "Style(Bold, Underline, FgRed, BgBlack)", s.String())
+}
+
+func TestStyleConflictingColors(t *testing.T) {
+	t.Parallel()
+	s := NewStyle().Fg(FgBlue).Fg(FgRed).Bold()
+	assertEqualS(t, "\x1b[1;31mtext\x1b[0m", s.Color().wrap(TrueColor, "text"))
+
+	s = NewStyle().With(FgBlue, BgWhite, FgRed, Italic, Bg256(3))
+	want := []Attribute{Italic, FgRed, Bg256(3)}
+	if got := s.Attributes(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v got %v", want, got)
+	}
+	if s = s.With(Reset, Bold); s != NewStyle().Bold() {
+		t.Fatalf("expected reset to clear the style got %s", s)
+	}
+}
+
+func TestStyleColorConversion(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		name string
+		got  Style
+		want Style
+	}{
+		{"bg as fg", NewStyle().Fg(BgHiCyan), NewStyle().Fg(FgHiCyan)},
+		{"fg as bg", NewStyle().Bg(FgGreen), NewStyle().Bg(BgGreen)},
+		{"256 as bg", NewStyle().Bg(Fg256(42)), NewStyle().Bg(Bg256(42))},
+		{"rgb as fg", NewStyle().Fg(BgRGB(1, 2, 3)), NewStyle().Fg(FgRGB(1, 2, 3))},
+		{"decoration ignored", NewStyle().Fg(Bold), NewStyle()},
+		{"invalid hex ignored", NewStyle().Fg(Hex("nope")), NewStyle()},
+		{"fg as underline", NewStyle().Ul(FgRed), NewStyle().Ul(Ul256(1))},
+		{"rgb as underline", NewStyle().Ul(FgRGB(1, 2, 3)), NewStyle().Ul(UlRGB(1, 2, 3))},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.got != tc.want {
+				t.Fatalf("want %s got %s", tc.want, tc.got)
+			}
+		})
+	}
+}
+
+func TestStyleMerge(t *testing.T) {
+	t.Parallel()
+	base := NewStyle().Fg(FgWhite).Bg(BgBlue).Bold()
+	override := NewStyle().Fg(FgYellow).Underline()
+	got := base.Merge(override)
+	want := NewStyle().Fg(FgYellow).Bg(BgBlue).Bold().Underline()
+	if got != want {
+		t.Fatalf("want %s got %s", want, got)
+	}
+	if base.Merge(NewStyle()) != base {
+		t.Fatal("merging an empty style should not change the base")
+	}
+	if base.Foreground() != FgWhite {
+		t.Fatal("merge must not change the receiver")
+	}
+}
+
+func TestStyleColor(t *testing.T) {
+	t.Parallel()
+	if !NewStyle().IsZero() {
+		t.Fatal("expected a new style to be empty")
+	}
+	c1 := NewStyle().Fg(FgRed).Bold().Color()
+	c2 := New(Bold, FgRed)
+	if c1 != c2 {
+		t.Fatal("expected the style to use the color cache")
+	}
+}
+
+func TestStyleUnderlines(t *testing.T) {
+	t.Parallel()
+	s := NewStyle().Underline().CurlyUnderline()
+	if s.Has(Underline) || !s.Has(CurlyUnderline) {
+		t.Fatalf("expected underline styles to replace each other got %s", s)
+	}
+	assertEqualS(t, "\x1b[4:3;58;5;208mx\x1b[0m", s.Ul(Ul256(208)).Color().wrap(TrueColor, "x"))
+	assertEqualS(t, "\x1b[21;53mx\x1b[0m", NewStyle().DoubleUnderline().Overline().Color().wrap(TrueColor, "x"))
+}
+
+func TestStyleOffAttributes(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		name  string
+		attrs []Attribute
+		want  string
+	}{
+		{"off clears on", []Attribute{Bold, NormalIntensity}, "\x1b[22mx\x1b[0m"},
+		{"on clears off", []Attribute{NotItalic, Italic}, "\x1b[3mx\x1b[0m"},
+		{"color clears default", []Attribute{DefaultFg, FgRed}, "\x1b[31mx\x1b[0m"},
+		{"default clears color", []Attribute{FgRed, DefaultFg}, "\x1b[39mx\x1b[0m"},
+		{"no underline", []Attribute{DottedUnderline, NoUnderline, Bold}, "\x1b[24;1mx\x1b[0m"},
+		{"several off codes", []Attribute{NotReversed, NotOverlined, DefaultBg}, "\x1b[27;55;49mx\x1b[0m"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assertEqualS(t, tc.want, NewStyle().With(tc.attrs...).Color().wrap(TrueColor, "x"))
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package color
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Alignment positions text within a table column.
+type Alignment int
+
+const (
+	AlignLeft Alignment = iota
+	AlignRight
+)
+
+// Column holds the settings of a table column.
+type Column struct {
+	// Style is applied to every cell of the column, the row style takes precedence where they conflict.
+	Style Style
+	// MaxWidth truncates longer cells to MaxWidth columns ending with an ellipsis. Zero means no limit.
+	MaxWidth int
+	Align    Alignment
+}
+
+// Table lays out rows of text in aligned columns. Cells may contain colored text, column widths are measured
+// with VisibleWidth so escape sequences and wide characters don't break the alignment.
+type Table struct {
+	// Header is printed before the rows with HeaderStyle. No header is printed when it's empty.
+	Header      []string
+	HeaderStyle Style
+	// RowStyles are applied to the rows in turn, two styles give zebra striping.
+	RowStyles []Style
+	// Columns holds the settings of each column, columns without an entry use the zero Column.
+	Columns []Column
+	// Separator is written between columns.
+	Separator string
+	// Ellipsis ends truncated cells.
+	Ellipsis string
+
+	rows [][]string
+}
+
+// NewTable returns a table with a bold header, two spaces between columns and "…" ending truncated cells.
+func NewTable(header ...string) *Table {
+	return &Table{
+		Header:      header,
+		HeaderStyle: NewStyle().Bold(),
+		Separator:   "  ",
+		Ellipsis:    "…",
+	}
+}
+
+// AddRow appends a row of cells.
+func (t *Table) AddRow(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// Print writes the table to the console. Styles follow the mode and profile of the console.
+func (t *Table) Print(c *Console) (int, error) {
+	on, p := c.policy()
+	return c.Write([]byte(t.render(on, p)))
+}
+
+// String returns the table with styles applied when colors are enabled globally.
+func (t *Table) String() string {
+	return t.render(Enabled(), ActiveProfile())
+}
+
+// render lays out the table for a terminal with profile p. Styles are left out and escape sequences in the
+// cells removed when on is false.
+func (t *Table) render(on bool, p Profile) string {
+	rows := t.rows
+	if len(t.Header) > 0 {
+		rows = append([][]string{t.Header}, rows...)
+	}
+	columns := 0
+	for _, row := range rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	cells := make([][]string, len(rows))
+	widths := make([]int, columns)
+	for i, row := range rows {
+		cells[i] = make([]string, columns)
+		for j, cell := range row {
+			if !on {
+				cell = Strip(cell)
+			}
+			if limit := t.column(j).MaxWidth; limit > 0 {
+				cell = Truncate(cell, limit, t.Ellipsis)
+			}
+			cells[i][j] = cell
+			if w := VisibleWidth(cell); w > widths[j] {
+				widths[j] = w
+			}
+		}
+	}
+
+	var b strings.Builder
+	for i, row := range cells {
+		var rowStyle Style
+		switch {
+		case len(t.Header) == 0:
+			rowStyle = t.rowStyle(i)
+		case i == 0:
+			rowStyle = t.HeaderStyle
+		default:
+			rowStyle = t.rowStyle(i - 1)
+		}
+		for j, cell := range row {
+			col := t.column(j)
+			if j > 0 {
+				b.WriteString(styled(on, p, rowStyle, t.Separator))
+			}
+			pad := strings.Repeat(" ", widths[j]-VisibleWidth(cell))
+			switch {
+			case col.Align == AlignRight:
+				cell = pad + cell
+			case j < columns-1 || rowStyle.Background() != 0:
+				// trailing spaces of the last column are only needed to extend a background
+				cell += pad
+			}
+			b.WriteString(styled(on, p, col.Style.Merge(rowStyle), cell))
+		}
+		b.WriteString(lineFeed)
+	}
+	return b.String()
+}
+
+// column returns the settings of column i.
+func (t *Table) column(i int) Column {
+	if i < len(t.Columns) {
+		return t.Columns[i]
+	}
+	return Column{}
+}
+
+// rowStyle returns the style of row i, not counting the header.
+func (t *Table) rowStyle(i int) Style {
+	if len(t.RowStyles) == 0 {
+		return NewStyle()
+	}
+	return t.RowStyles[i%len(t.RowStyles)]
+}
+
+// styled decorates s with style when on is true.
+func styled(on bool, p Profile, style Style, s string) string {
+	if !on || style.IsZero() || s == "" {
+		return s
+	}
+	return style.Color().wrap(p, s)
+}
+
+// Truncate shortens s to at most width columns, replacing the end with tail when text is cut. Escape
+// sequences aren't cut and the ones after the cut are kept, so a color started in s is still reset.
+func Truncate(s string, width int, tail string) string {
+	if VisibleWidth(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	if VisibleWidth(tail) > width {
+		tail = Truncate(tail, width, "")
+	}
+	limit := width - VisibleWidth(tail)
+	var b strings.Builder
+	var st stripper
+	used := 0
+	cut := false
+	for i := 0; i < len(s); {
+		if st.state != stateText || s[i] == esc {
+			st.step(s[i])
+			b.WriteByte(s[i])
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if !cut {
+			if w := runeWidth(r); used+w > limit {
+				cut = true
+				b.WriteString(tail)
+			} else {
+				used += w
+				b.WriteString(s[i : i+size])
+			}
+		}
+		i += size
+	}
+	return b.String()
+}
@@ -0,0 +1,97 @@
+package color
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTable(t *testing.T) {
+	t.Parallel()
+	tbl := NewTable("Name", "Dynos")
+	tbl.Columns = []Column{{}, {Align: AlignRight}}
+	tbl.AddRow(New(FgGreen).wrap(TrueColor, "web"), "2")
+	tbl.AddRow("worker", "10")
+	want := "Name    Dynos\n" +
+		"web         2\n" +
+		"worker     10\n"
+	assertEqualS(t, want, tbl.render(false, TrueColor))
+
+	want = "\x1b[1mName  \x1b[0m\x1b[1m  \x1b[0m\x1b[1mDynos\x1b[0m\n" +
+		"\x1b[32mweb\x1b[0m         2\n" +
+		"worker     10\n"
+	assertEqualS(t, want, tbl.render(true, TrueColor))
+}
+
+func TestTableStyles(t *testing.T) {
+	t.Parallel()
+	tbl := &Table{
+		RowStyles: []Style{NewStyle(), NewStyle().Bg(BgBlue)},
+		Columns:   []Column{{Style: NewStyle().Fg(FgRed)}},
+		Separator: "|",
+	}
+	tbl.AddRow("a", "b")
+	tbl.AddRow("ccc", "d")
+	want := "\x1b[31ma  \x1b[0m|b\n" +
+		"\x1b[31;44mccc\x1b[0m\x1b[44m|\x1b[0m\x1b[44md\x1b[0m\n"
+	assertEqualS(t, want, tbl.render(true, TrueColor))
+}
+
+func TestTableHeaderRowStyles(t *testing.T) {
+	t.Parallel()
+	tbl := &Table{
+		Header:      []string{"h"},
+		HeaderStyle: NewStyle().Bold(),
+		RowStyles:   []Style{NewStyle(), NewStyle().Bg(BgBlue)},
+	}
+	tbl.AddRow("a")
+	tbl.AddRow("b")
+	tbl.AddRow("c")
+	want := "\x1b[1mh\x1b[0m\n" +
+		"a\n" +
+		"\x1b[44mb\x1b[0m\n" +
+		"c\n"
+	assertEqualS(t, want, tbl.render(true, TrueColor))
+}
+
+func TestTableTruncate(t *testing.T) {
+	t.Parallel()
+	tbl := NewTable()
+	tbl.Columns = []Column{{MaxWidth: 5}}
+	tbl.AddRow("abcdefgh", "x")
+	tbl.AddRow("ab", "y")
+	assertEqualS(t, "abcd…  x\nab     y\n", tbl.render(false, TrueColor))
+}
+
+func TestTableConsole(t *testing.T) {
+	t.Parallel()
+	var buff bytes.Buffer
+	cons := NewConsoleWriter(&buff, WithMode(Never))
+	tbl := NewTable("A")
+	tbl.AddRow(New(FgRed).wrap(TrueColor, "日本"))
+	_, _ = tbl.Print(cons)
+	assertEqualS(t, "A\n日本\n", buff.String())
+}
+
+func TestTruncate(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		name  string
+		in    string
+		width int
+		want  string
+	}{
+		{"fits", "abc", 3, "abc"},
+		{"cut", "abcdef", 4, "abc…"},
+		{"colored", "\x1b[31mabcdef\x1b[0m", 4, "\x1b[31mabc…\x1b[0m"},
+		{"escapes after cut kept", "\x1b[31mab\x1b[1mcdef\x1b[0m", 3, "\x1b[31mab\x1b[1m…\x1b[0m"},
+		{"wide", "日本語", 5, "日本…"},
+		{"wide boundary", "日本語", 4, "日…"},
+		{"tail too wide", "abcdef", 0, ""},
+		{"negative width", "hello", -1, ""},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assertEqualS(t, tc.want, Truncate(tc.in, tc.width, "…"))
+		})
+	}
+}
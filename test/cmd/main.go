@@ -12,7 +12,7 @@ func main() {
 	color.HiMagenta("hello megenta %s", "foo")
 	color.HiCyan("hello cyan %d", 10)
 
-	emphasized := color.New(color.FgBlue, color.FgRed, color.Bold).SprintFunc()
+	emphasized := color.NewStyle().Fg(color.FgBlue).Fg(color.FgRed).Bold().Color().SprintFunc()
 	_, _ = fmt.Fprintln(color.Stdout(), "Wow! This is", emphasized("exciting!"))
 
 	_, _ = color.Stdout().Println(color.New(), "no color at all")
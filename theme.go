@@ -0,0 +1,242 @@
+package color
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Semantic roles found in every preset theme.
+const (
+	RoleError     = "error"
+	RoleWarning   = "warning"
+	RoleSuccess   = "success"
+	RoleInfo      = "info"
+	RoleMuted     = "muted"
+	RoleHighlight = "highlight"
+	RolePrompt    = "prompt"
+)
+
+// Theme maps semantic roles such as "error" or "prompt" to styles, so that output can be restyled in one
+// place. Roles aren't limited to the predefined ones, a theme may hold any name. A role missing from the
+// theme has the empty style and prints plain text.
+//
+// Use Color with a Console:
+//
+//	color.Stderr().Println(theme.Color(color.RoleError), "failed")
+type Theme map[string]Style
+
+// DefaultTheme returns the theme used by most command line tools: red errors, yellow warnings and so on.
+func DefaultTheme() Theme {
+	return Theme{
+		RoleError:     NewStyle().Bold().Fg(FgRed),
+		RoleWarning:   NewStyle().Fg(FgYellow),
+		RoleSuccess:   NewStyle().Fg(FgGreen),
+		RoleInfo:      NewStyle().Fg(FgCyan),
+		RoleMuted:     NewStyle().Fg(FgHiBlack),
+		RoleHighlight: NewStyle().Bold(),
+		RolePrompt:    NewStyle().Bold().Fg(FgBlue),
+	}
+}
+
+// HighContrastTheme returns a theme which puts important roles on solid backgrounds and avoids faint or dark
+// text.
+func HighContrastTheme() Theme {
+	return Theme{
+		RoleError:     NewStyle().Bold().Fg(FgHiWhite).Bg(BgRed),
+		RoleWarning:   NewStyle().Bold().Fg(FgBlack).Bg(BgHiYellow),
+		RoleSuccess:   NewStyle().Bold().Fg(FgBlack).Bg(BgHiGreen),
+		RoleInfo:      NewStyle().Bold().Fg(FgHiWhite).Bg(BgBlue),
+		RoleMuted:     NewStyle().Fg(FgWhite),
+		RoleHighlight: NewStyle().Bold().Underline().Fg(FgHiWhite),
+		RolePrompt:    NewStyle().Bold().Fg(FgHiCyan),
+	}
+}
+
+// ColorblindTheme returns a theme using the Okabe-Ito palette, whose colors remain distinguishable with the
+// common forms of color blindness. Errors are bold and warnings underlined so they don't rely on color
+// alone.
+func ColorblindTheme() Theme {
+	return Theme{
+		RoleError:     NewStyle().Bold().Fg(Hex("#d55e00")),
+		RoleWarning:   NewStyle().Underline().Fg(Hex("#e69f00")),
+		RoleSuccess:   NewStyle().Fg(Hex("#009e73")),
+		RoleInfo:      NewStyle().Fg(Hex("#56b4e9")),
+		RoleMuted:     NewStyle().Fg(FgHiBlack),
+		RoleHighlight: NewStyle().Bold().Fg(Hex("#f0e442")),
+		RolePrompt:    NewStyle().Bold().Fg(Hex("#0072b2")),
+	}
+}
+
+// Style returns the style of role.
+func (t Theme) Style(role string) Style {
+	return t[role]
+}
+
+// Color returns the Color of role, which can be passed to the Console methods. A role with the empty style
+// still gives a Color writing reset codes, Sprint leaves the text of such roles unchanged.
+func (t Theme) Color(role string) *Color {
+	return t[role].Color()
+}
+
+// Sprint returns text decorated with the style of role, see Color.Sprint.
+func (t Theme) Sprint(role string, a ...interface{}) string {
+	return t.render(role, fmt.Sprint(a...))
+}
+
+// Sprintf formats according to a format specifier and returns text decorated with the style of role.
+func (t Theme) Sprintf(role string, format string, a ...interface{}) string {
+	return t.render(role, fmt.Sprintf(format, a...))
+}
+
+// Sprintln returns text decorated with the style of role and terminated by a line feed.
+func (t Theme) Sprintln(role string, a ...interface{}) string {
+	return appendLineFeed(t.render(role, fmt.Sprint(a...)))
+}
+
+// render decorates s with the style of role when colors are enabled globally. Roles with the empty style,
+// including missing ones, leave s unchanged.
+func (t Theme) render(role, s string) string {
+	style := t[role]
+	if style.IsZero() {
+		return s
+	}
+	return style.Color().render(Enabled(), ActiveProfile(), s)
+}
+
+// Merge returns a copy of t with the roles of o added. Roles found in both themes take the style of o.
+func (t Theme) Merge(o Theme) Theme {
+	merged := make(Theme, len(t)+len(o))
+	for role, s := range t {
+		merged[role] = s
+	}
+	for role, s := range o {
+		merged[role] = s
+	}
+	return merged
+}
+
+// LoadTheme reads a theme from a JSON, YAML or TOML file, chosen by the extension of path (.json, .yaml,
+// .yml or .toml). Roles the file doesn't mention keep their style from DefaultTheme.
+func LoadTheme(path string) (Theme, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	t, err := ParseTheme(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return DefaultTheme().Merge(t), nil
+}
+
+// ParseTheme parses a theme in format "json", "yaml" or "toml". Each role maps to a style description as
+// accepted by ParseStyle:
+//
+//	{"error": "bold red", "muted": "gray"}   // JSON
+//	error: bold red                          # YAML
+//	error = "bold red"                       # TOML
+//
+// Only flat mappings of roles to strings are supported in YAML and TOML. Hex colors must be quoted in YAML
+// since # starts a comment.
+func ParseTheme(data []byte, format string) (Theme, error) {
+	var entries map[string]string
+	var err error
+	switch format {
+	case "json":
+		err = json.Unmarshal(data, &entries)
+	case "yaml", "yml":
+		entries, err = parseKeyValues(data, ":")
+	case "toml":
+		entries, err = parseKeyValues(data, "=")
+	default:
+		return nil, fmt.Errorf("unsupported theme format %q, expected json, yaml or toml", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	t := make(Theme, len(entries))
+	for role, desc := range entries {
+		s, err := ParseStyle(desc)
+		if err != nil {
+			return nil, fmt.Errorf("role %q: %w", role, err)
+		}
+		t[role] = s
+	}
+	return t, nil
+}
+
+// parseKeyValues reads lines of the form key<sep>value, the subset of YAML and TOML a theme needs. Values may
+// be quoted, blank lines and comments starting with # are skipped.
+func parseKeyValues(data []byte, sep string) (map[string]string, error) {
+	entries := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(stripComment(scanner.Text()))
+		if text == "" || text == "---" {
+			continue
+		}
+		i := strings.Index(text, sep)
+		if i < 0 {
+			return nil, fmt.Errorf("line %d: expected role %s style, got %q", line, sep, text)
+		}
+		key, err := unquote(strings.TrimSpace(text[:i]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		value, err := unquote(strings.TrimSpace(text[i+len(sep):]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if key == "" || value == "" {
+			return nil, fmt.Errorf("line %d: expected role %s style, got %q", line, sep, text)
+		}
+		entries[key] = value
+	}
+	return entries, scanner.Err()
+}
+
+// stripComment removes a comment starting with # from line, unless the # is quoted.
+func stripComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// unquote removes single or double quotes around s.
+func unquote(s string) (string, error) {
+	if len(s) < 2 {
+		return s, nil
+	}
+	switch s[0] {
+	case '"':
+		u, err := strconv.Unquote(s)
+		if err != nil {
+			return "", fmt.Errorf("invalid string %s", s)
+		}
+		return u, nil
+	case '\'':
+		if s[len(s)-1] != '\'' {
+			return "", fmt.Errorf("unterminated string %s", s)
+		}
+		return s[1 : len(s)-1], nil
+	}
+	return s, nil
+}
@@ -0,0 +1,130 @@
+package color
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestThemePresets(t *testing.T) {
+	t.Parallel()
+	roles := []string{RoleError, RoleWarning, RoleSuccess, RoleInfo, RoleMuted, RoleHighlight, RolePrompt}
+	for name, theme := range map[string]Theme{
+		"default":       DefaultTheme(),
+		"high contrast": HighContrastTheme(),
+		"colorblind":    ColorblindTheme(),
+	} {
+		for _, role := range roles {
+			if theme.Style(role).IsZero() {
+				t.Fatalf("%s theme is missing role %s", name, role)
+			}
+		}
+	}
+}
+
+func TestThemeColor(t *testing.T) {
+	t.Parallel()
+	theme := DefaultTheme()
+	if theme.Color(RoleError) != New(Bold, FgRed) {
+		t.Fatal("expected the theme to use the color cache")
+	}
+	var buff bytes.Buffer
+	cons := newMockConsole(&buff)
+	_, _ = cons.Print(theme.Color(RoleWarning), "careful")
+	assertEqualS(t, "\x1b[33mcareful\x1b[0m", buff.String())
+	cons.SetMode(Always)
+	assertEqualS(t, "\x1b[33mx\x1b[0m", cons.Sprint(theme.Color(RoleWarning), "x"))
+	assertEqualS(t, "\x1b[0mx\x1b[0m", cons.Sprint(theme.Color("unknown"), "x"))
+	assertEqualS(t, "x", theme.Sprint("unknown", "x"))
+	assertEqualS(t, "x 1", theme.Sprintf("unknown", "x %d", 1))
+	assertEqualS(t, "x\n", theme.Sprintln("unknown", "x"))
+}
+
+func TestParseTheme(t *testing.T) {
+	t.Parallel()
+	want := Theme{
+		RoleError: NewStyle().Bold().Fg(FgRed),
+		RoleMuted: NewStyle().Fg(FgRGB(0x88, 0x88, 0x88)),
+		"header":  NewStyle().Underline(),
+	}
+	tt := []struct {
+		format string
+		data   string
+	}{
+		{"json", `{"error": "bold red", "muted": "#888", "header": "underline"}`},
+		{"yaml", "# my theme\n---\nerror: bold red\nmuted: '#888' # gray\n\nheader: \"underline\"\n"},
+		{"toml", "# my theme\nerror = \"bold red\"\nmuted = \"#888\"\nheader = 'underline'\n"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.format, func(t *testing.T) {
+			got, err := ParseTheme([]byte(tc.data), tc.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("want %v got %v", want, got)
+			}
+			for role, s := range want {
+				if got[role] != s {
+					t.Fatalf("%s: want %s got %s", role, s, got[role])
+				}
+			}
+		})
+	}
+}
+
+func TestParseThemeErrors(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		name   string
+		format string
+		data   string
+		want   string
+	}{
+		{"unknown format", "ini", "", `unsupported theme format "ini", expected json, yaml or toml`},
+		{"bad style", "json", `{"error": "bold orange"}`,
+			`role "error": invalid style "bold orange": unknown attribute "orange"`},
+		{"missing separator", "yaml", "error: red\nwarning yellow\n",
+			`line 2: expected role : style, got "warning yellow"`},
+		{"unquoted hex", "yaml", "error: #f00\n", `line 1: expected role : style, got "error:"`},
+		{"bad string", "toml", `error = "red`, `line 1: invalid string "red`},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseTheme([]byte(tc.data), tc.format)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			assertEqualS(t, tc.want, err.Error())
+		})
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "theme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "theme.yml")
+	if err := ioutil.WriteFile(path, []byte("error: magenta\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	theme, err := LoadTheme(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if theme.Style(RoleError) != NewStyle().Fg(FgMagenta) {
+		t.Fatalf("expected the file to override the error role got %s", theme.Style(RoleError))
+	}
+	if theme.Style(RoleWarning) != DefaultTheme().Style(RoleWarning) {
+		t.Fatal("expected roles missing from the file to keep the default style")
+	}
+
+	if _, err := LoadTheme(filepath.Join(dir, "missing.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected a not exist error got %v", err)
+	}
+}
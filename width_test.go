@@ -0,0 +1,34 @@
+package color
+
+import "testing"
+
+func TestVisibleWidth(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		name string
+		in   string
+		want int
+	}{
+		{"empty", "", 0},
+		{"ascii", "hello", 5},
+		{"colored", New(FgRed).wrap(TrueColor, "hello"), 5},
+		{"hyperlink", "\x1b]8;;http://example.com\x1b\\link\x1b]8;;\x1b\\", 4},
+		{"latin", "héllo", 5},
+		{"combining", "he\u0301llo", 5},
+		{"cjk", "日本語", 6},
+		{"hangul", "한국어", 6},
+		{"fullwidth", "ＡＢ", 4},
+		{"halfwidth katakana", "ｱｲ", 2},
+		{"emoji", "🚀 go", 5},
+		{"zero width joiner", "a\u200db", 2},
+		{"control", "a\tb\n", 2},
+		{"colored cjk", "\x1b[1;38;5;208m中文\x1b[0m!", 5},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := VisibleWidth(tc.in); got != tc.want {
+				t.Fatalf("want %d got %d", tc.want, got)
+			}
+		})
+	}
+}